@@ -0,0 +1,346 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TrueTickets/api-aggregator/internal/bootstrap"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/openapi"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// defaultConfigPath returns the configuration file path from the environment, or config.yaml
+func defaultConfigPath() string {
+	if configPath := os.Getenv("API_AGGREGATOR_CONFIG_PATH"); configPath != "" {
+		return configPath
+	}
+	return "config.yaml"
+}
+
+// runCommand runs a CLI subcommand and returns the process exit code. Commands load the
+// configuration from configPath unless given their own -config flag.
+func runCommand(args []string, configPath string, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], configPath, stdout, stderr)
+	case "routes":
+		return runRoutes(args[1:], configPath, stdout, stderr)
+	case "config":
+		if len(args) < 2 || args[1] != "print" {
+			_, _ = fmt.Fprintln(stderr, "Usage: api-aggregator config print [-config file] [-format yaml|json]")
+			return exitUsage
+		}
+		return runConfigPrint(args[2:], configPath, stdout, stderr)
+	case "openapi":
+		return runOpenAPI(args[1:], configPath, stdout, stderr)
+	case "bootstrap":
+		return runBootstrap(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitOK
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+}
+
+// printUsage prints the available commands
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprint(w, `Usage: api-aggregator [-config file] [command]
+
+Without a command, the server is started.
+
+Commands:
+  validate      Load, validate and lint the configuration
+  routes        List the endpoints with their backends and timeouts
+  config print  Print the effective configuration, with defaults and environment overrides
+  openapi       Print the OpenAPI document generated from the configuration
+  bootstrap     Generate a starter configuration from upstream OpenAPI documents
+
+The configuration file defaults to API_AGGREGATOR_CONFIG_PATH, or config.yaml.
+validate, routes and openapi take -offline to check a configuration without
+reading its certificates and secrets.
+`)
+}
+
+// configFlag registers the configuration file flag of a command
+func configFlag(flags *flag.FlagSet, defaultPath string) *string {
+	return flags.String("config", defaultPath, "configuration file, directory or glob pattern")
+}
+
+// offlineFlag registers the flag of a command to load the configuration without its
+// certificates and secrets
+func offlineFlag(flags *flag.FlagSet) *bool {
+	return flags.Bool("offline", false, "do not read certificates, key files and secrets")
+}
+
+// loadConfig loads the configuration with the environment overrides applied, reporting errors
+func loadConfig(configPath string, offline bool, stderr io.Writer) (*config.Config, bool) {
+	cfg, err := config.LoadConfigWithOptions(configPath, config.LoadOptions{Env: true, Offline: offline})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to load configuration: %v\n", err)
+		return nil, false
+	}
+	return cfg, true
+}
+
+// runValidate loads, validates and lints a configuration, for use in CI.
+// Lint errors fail the command, and warnings too when strict.
+func runValidate(args []string, defaultPath string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := configFlag(flags, defaultPath)
+	strict := flags.Bool("strict", false, "fail on lint warnings")
+	offline := offlineFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	cfg, ok := loadConfig(*configPath, *offline, stderr)
+	if !ok {
+		return exitError
+	}
+
+	issues := cfg.Lint()
+	for _, issue := range issues {
+		_, _ = fmt.Fprintln(stderr, issue)
+	}
+	if config.HasLintErrors(issues) || (*strict && len(issues) > 0) {
+		_, _ = fmt.Fprintf(stderr, "%s: configuration has %d lint issues\n", *configPath, len(issues))
+		return exitError
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s: configuration is valid (%d endpoints)\n", *configPath, len(cfg.Endpoints))
+	return exitOK
+}
+
+// runRoutes prints a table of the endpoints, one row per backend
+func runRoutes(args []string, defaultPath string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("routes", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := configFlag(flags, defaultPath)
+	offline := offlineFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	cfg, ok := loadConfig(*configPath, *offline, stderr)
+	if !ok {
+		return exitError
+	}
+
+	table := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "METHOD\tENDPOINT\tTIMEOUT\tBACKEND\tBACKEND TIMEOUT")
+	for _, endpoint := range cfg.Endpoints {
+		method, path, timeout := endpoint.Method, endpoint.Endpoint, endpoint.Timeout.String()
+		for _, backend := range endpoint.Backends {
+			_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+				method, path, timeout, backendRoute(backend), durationOrDash(backend.Timeout))
+			// The endpoint columns are only written on its first row
+			method, path, timeout = "", "", ""
+		}
+	}
+	if err := table.Flush(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to write routes: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// backendRoute describes the request made to a backend
+func backendRoute(backend config.Backend) string {
+	route := backend.Method + " " + backend.Host + backend.URLPattern
+	if backend.Name != "" {
+		route = backend.Name + ": " + route
+	}
+	if backend.Optional {
+		route += " (optional)"
+	}
+	return route
+}
+
+// durationOrDash formats a duration, or a dash if it is not set
+func durationOrDash(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.String()
+}
+
+// runConfigPrint prints the effective configuration, with secrets redacted
+func runConfigPrint(args []string, defaultPath string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := configFlag(flags, defaultPath)
+	format := flags.String("format", "yaml", "output format: yaml or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != "json" && *format != "yaml" {
+		_, _ = fmt.Fprintf(stderr, "invalid format %q\n", *format)
+		return exitUsage
+	}
+
+	cfg, ok := loadConfig(*configPath, false, stderr)
+	if !ok {
+		return exitError
+	}
+
+	data, err := cfg.Dump()
+	if err == nil && *format == "json" {
+		data, err = yamlToJSON(data)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to encode configuration: %v\n", err)
+		return exitError
+	}
+
+	if _, err := stdout.Write(data); err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to write configuration: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// yamlToJSON converts a YAML document to indented JSON, keeping the YAML field names
+func yamlToJSON(data []byte) ([]byte, error) {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// runOpenAPI prints the OpenAPI document for a configuration
+func runOpenAPI(args []string, defaultPath string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := configFlag(flags, defaultPath)
+	format := flags.String("format", "json", "output format: json or yaml")
+	output := flags.String("output", "", "write the document to this file instead of standard output")
+	offline := offlineFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != "json" && *format != "yaml" {
+		_, _ = fmt.Fprintf(stderr, "invalid format %q\n", *format)
+		return exitUsage
+	}
+
+	cfg, ok := loadConfig(*configPath, *offline, stderr)
+	if !ok {
+		return exitError
+	}
+
+	doc, err := openapi.Generate(cfg)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to generate OpenAPI document: %v\n", err)
+		return exitError
+	}
+
+	data, err := encodeDocument(doc, *format)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to encode OpenAPI document: %v\n", err)
+		return exitError
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0o644) //nolint:gosec // The document is meant to be published
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to write OpenAPI document: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runBootstrap generates a starter configuration from upstream OpenAPI documents.
+// Operations needing attention are reported on standard error.
+func runBootstrap(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bootstrap", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: api-aggregator bootstrap [-output file] spec...")
+		flags.PrintDefaults()
+	}
+	output := flags.String("output", "", "write the configuration to this file instead of standard output")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	specs := make([]bootstrap.Spec, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		spec, err := bootstrap.LoadSpec(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "failed to load OpenAPI document: %v\n", err)
+			return exitError
+		}
+		specs = append(specs, spec)
+	}
+
+	result := bootstrap.Generate(specs)
+	for _, note := range result.Notes {
+		_, _ = fmt.Fprintf(stderr, "warning: %s\n", note)
+	}
+
+	data, err := result.YAML()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to encode configuration: %v\n", err)
+		return exitError
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0o600)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to write configuration: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// encodeDocument encodes a document as indented JSON, or as YAML through its JSON form
+func encodeDocument(doc interface{}, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return append(data, '\n'), nil
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
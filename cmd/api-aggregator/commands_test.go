@@ -0,0 +1,207 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+endpoints:
+  - endpoint: "/users/{user}"
+    timeout: 2s
+    backends:
+      - name: users
+        host: "http://users"
+        url_pattern: "/users/{user}"
+        timeout: 500ms
+        auth:
+          type: bearer
+          token:
+            value: secret-token
+      - host: "http://posts"
+        url_pattern: "/posts"
+        optional: true
+`
+
+// writeTestConfig writes a configuration file and returns its path
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunCommand(t *testing.T) {
+	validConfig := writeTestConfig(t, testConfigYAML)
+	invalidConfig := writeTestConfig(t, "endpoints:\n  - endpoint: /users\n")
+	// Certificates and secrets only available where the service is deployed
+	deployedConfig := writeTestConfig(t, `
+tls:
+  cert_file: /run/certs/server.pem
+  key_file: /run/certs/server-key.pem
+  min_version: "1.2"
+endpoints:
+  - endpoint: /users
+    backends:
+      - host: "https://users"
+        tls:
+          ca_file: /run/certs/ca.pem
+        auth:
+          type: bearer
+          token:
+            env: API_AGGREGATOR_TEST_UNSET_TOKEN
+      - host: "https://accounts"
+        group: account
+        auth:
+          type: basic
+          username: aggregator
+          password:
+            value: ${file:/run/secrets/accounts-password}
+`)
+	duplicateConfig := writeTestConfig(t, `
+endpoints:
+  - endpoint: /users
+    backends:
+      - host: "http://users"
+  - endpoint: /users
+    backends:
+      - host: "http://users"
+`)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedOutput []string
+		expectedError  string
+	}{
+		{
+			name:           "validate valid configuration",
+			args:           []string{"validate", "-config", validConfig},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"configuration is valid (1 endpoints)"},
+		},
+		{
+			name:          "validate invalid configuration",
+			args:          []string{"validate", "--config", invalidConfig},
+			expectedCode:  exitError,
+			expectedError: "endpoint /users: at least one backend is required",
+		},
+		{
+			name:          "validate without certificates and secrets",
+			args:          []string{"validate", "-config", deployedConfig},
+			expectedCode:  exitError,
+			expectedError: "failed to load configuration",
+		},
+		{
+			name:           "validate offline",
+			args:           []string{"validate", "-offline", "-config", deployedConfig},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"configuration is valid (1 endpoints)"},
+		},
+		{
+			name:           "routes offline",
+			args:           []string{"routes", "-offline", "-config", deployedConfig},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"GET     /users    10s      GET https://users/users"},
+		},
+		{
+			name:           "openapi offline",
+			args:           []string{"openapi", "-offline", "-config", deployedConfig},
+			expectedCode:   exitOK,
+			expectedOutput: []string{`"/users"`},
+		},
+		{
+			name:          "validate with lint errors",
+			args:          []string{"validate", "-config", duplicateConfig},
+			expectedCode:  exitError,
+			expectedError: "error: endpoint GET /users: duplicate endpoint",
+		},
+		{
+			name:           "validate with lint warnings",
+			args:           []string{"validate", "-config", validConfig},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"configuration is valid"},
+			expectedError:  "warning: endpoint GET /users/{user}: backends 0, 1 have neither group nor concat",
+		},
+		{
+			name:          "validate strictly with lint warnings",
+			args:          []string{"validate", "-strict", "-config", validConfig},
+			expectedCode:  exitError,
+			expectedError: "configuration has 1 lint issues",
+		},
+		{
+			name:         "routes",
+			args:         []string{"routes", "-config", validConfig},
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"METHOD  ENDPOINT       TIMEOUT  BACKEND",
+				"GET     /users/{user}  2s       users: GET http://users/users/{user}  500ms",
+				"                                GET http://posts/posts (optional)     -",
+			},
+		},
+		{
+			name:         "config print",
+			args:         []string{"config", "print", "-config", validConfig},
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"log_level: info",
+				"method: GET",
+				"value: REDACTED",
+			},
+		},
+		{
+			name:           "config print as JSON",
+			args:           []string{"config", "print", "-config", validConfig, "-format", "json"},
+			expectedCode:   exitOK,
+			expectedOutput: []string{`"log_level": "info"`},
+		},
+		{
+			name:          "config without print",
+			args:          []string{"config"},
+			expectedCode:  exitUsage,
+			expectedError: "Usage: api-aggregator config print",
+		},
+		{
+			name:          "unknown command",
+			args:          []string{"serve"},
+			expectedCode:  exitUsage,
+			expectedError: `unknown command "serve"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runCommand(tt.args, "config.yaml", &stdout, &stderr)
+
+			assert.Equal(t, tt.expectedCode, code, stderr.String())
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, stdout.String(), expected)
+			}
+			if tt.expectedError != "" {
+				assert.Contains(t, stderr.String(), tt.expectedError)
+			}
+			assert.NotContains(t, stdout.String(), "secret-token")
+		})
+	}
+}
+
+func TestRunCommand_DefaultConfigPath(t *testing.T) {
+	t.Setenv("API_AGGREGATOR_LOG_LEVEL", "debug")
+	configPath := writeTestConfig(t, testConfigYAML)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitOK, runCommand([]string{"config", "print"}, configPath, &stdout, &stderr), stderr.String())
+
+	// Environment overrides are part of the effective configuration
+	assert.Contains(t, stdout.String(), "log_level: debug")
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
 )
 
 func setupLogger(format string) {
@@ -41,3 +43,14 @@ func setupLogLevel(logLevel string) {
 	}
 	zerolog.SetGlobalLevel(level)
 }
+
+// logLintIssues logs the risky setups found in the configuration; the service still starts
+func logLintIssues(cfg *config.Config) {
+	for _, issue := range cfg.Lint() {
+		event := log.Warn()
+		if issue.Severity == config.LintError {
+			event = log.Error()
+		}
+		event.Str("location", issue.Location).Msg("Configuration lint: " + issue.Message)
+	}
+}
@@ -17,15 +17,20 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/TrueTickets/api-aggregator/internal/config"
 	"github.com/TrueTickets/api-aggregator/internal/telemetry"
@@ -40,14 +45,29 @@ const (
 )
 
 func main() {
+	// Arguments not starting with a flag name a command
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		os.Exit(runCommand(args, defaultConfigPath(), os.Stdout, os.Stderr))
+	}
+
 	// Get config path for reloading
-	configPath := os.Getenv("API_AGGREGATOR_CONFIG_PATH")
-	if configPath == "" {
-		configPath = "config.yaml"
+	flags := flag.NewFlagSet("api-aggregator", flag.ContinueOnError)
+	flags.Usage = func() { printUsage(os.Stderr) }
+	configPath := configFlag(flags, defaultConfigPath())
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(exitOK)
+		}
+		os.Exit(exitUsage)
+	}
+	if flags.NArg() > 0 {
+		// Commands default to the configuration file given before them
+		os.Exit(runCommand(flags.Args(), *configPath, os.Stdout, os.Stderr))
 	}
 
 	// Load configuration
-	cfg, err := config.LoadConfigFromEnv()
+	cfg, err := config.LoadConfigWithEnv(*configPath)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
@@ -55,28 +75,46 @@ func main() {
 	// Initialize logger with format and level
 	setupLogger(cfg.LogFormat)
 	setupLogLevel(cfg.LogLevel)
+	logLintIssues(cfg)
 
 	// Initialize telemetry
 	tel := initializeTelemetry(cfg)
 	defer shutdownTelemetry(tel)
 
 	// Create and start server with reloading capability
-	runReloadableServer(cfg, tel, configPath)
+	runReloadableServer(cfg, tel, *configPath)
 }
 
 func runReloadableServer(cfg *config.Config, tel *telemetry.Provider, configPath string) {
+	// Load listener certificates; they are reloaded along with the configuration
+	var certs *tlsReloader
+	if cfg.TLS.Enabled() {
+		var err error
+		certs, err = newTLSReloader(cfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load TLS configuration")
+		}
+	}
+
 	// Create reloadable server
-	reloadableSrv := newReloadableServer(cfg, tel, configPath)
+	reloadableSrv := newReloadableServer(cfg, tel, configPath, certs)
 
 	// Create HTTP server
-	httpServer := createHTTPServer(cfg, reloadableSrv)
+	httpServer := createHTTPServer(cfg, reloadableSrv, certs)
 
 	// Start server and wait for shutdown with config reloading
 	startServerAndWaitWithReload(httpServer, cfg, reloadableSrv)
 }
 
-func createHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
-	return &http.Server{
+func createHTTPServer(cfg *config.Config, handler http.Handler, certs *tlsReloader) *http.Server {
+	// Cleartext HTTP/2 is negotiated by the handler on plain HTTP listeners
+	if cfg.H2C {
+		handler = h2c.NewHandler(handler, &http2.Server{
+			IdleTimeout: httpIdleTimeoutSeconds * time.Second,
+		})
+	}
+
+	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%s", cfg.Port),
 		Handler:           handler,
 		ReadHeaderTimeout: httpReadTimeoutSeconds * time.Second,
@@ -84,14 +122,36 @@ func createHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
 		IdleTimeout:       httpIdleTimeoutSeconds * time.Second,
 		MaxHeaderBytes:    1 << httpMaxHeaderBytesShift, // 1MB
 	}
+
+	if certs != nil {
+		httpServer.TLSConfig = certs.serverConfig()
+	}
+
+	if !cfg.HTTP2Enabled() {
+		// A non-nil empty map disables HTTP/2 over TLS
+		httpServer.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	}
+
+	return httpServer
 }
 
 func startServerAndWaitWithReload(httpServer *http.Server, cfg *config.Config, reloadableSrv *reloadableServer) {
 	// Start server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		log.Info().Str("port", cfg.Port).Msg("Starting API aggregator server")
-		err := httpServer.ListenAndServe()
+		log.Info().
+			Str("port", cfg.Port).
+			Bool("tls", httpServer.TLSConfig != nil).
+			Bool("h2c", cfg.H2C).
+			Msg("Starting API aggregator server")
+
+		var err error
+		if httpServer.TLSConfig != nil {
+			// Certificates are served by the TLS configuration
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
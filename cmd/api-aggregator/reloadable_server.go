@@ -33,10 +33,16 @@ type reloadableServer struct {
 	cfg        *config.Config
 	tel        *telemetry.Provider
 	configPath string
+	certs      *tlsReloader
 }
 
 // newReloadableServer creates a new reloadable server
-func newReloadableServer(cfg *config.Config, tel *telemetry.Provider, configPath string) *reloadableServer {
+func newReloadableServer(
+	cfg *config.Config,
+	tel *telemetry.Provider,
+	configPath string,
+	certs *tlsReloader,
+) *reloadableServer {
 	srv := server.New(server.Config{
 		Config: cfg,
 		Tracer: tel.Tracer(),
@@ -49,6 +55,7 @@ func newReloadableServer(cfg *config.Config, tel *telemetry.Provider, configPath
 		cfg:        cfg,
 		tel:        tel,
 		configPath: configPath,
+		certs:      certs,
 	}
 }
 
@@ -64,15 +71,33 @@ func (rs *reloadableServer) reload() error {
 	log.Info().Msg("Reloading configuration...")
 
 	// Load new config
-	newCfg, err := config.LoadConfig(rs.configPath)
+	newCfg, err := config.LoadConfigWithEnv(rs.configPath)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to reload configuration")
 		return err
 	}
 
+	// Swap listener certificates; enabling or disabling TLS requires a restart
+	if rs.certs != nil {
+		if !newCfg.TLS.Enabled() {
+			log.Warn().Msg("TLS cannot be disabled without a restart, keeping current certificates")
+		} else if err := rs.certs.reload(newCfg); err != nil {
+			log.Error().Err(err).Msg("Failed to reload TLS certificates")
+			return err
+		}
+	} else if newCfg.TLS.Enabled() {
+		log.Warn().Msg("TLS cannot be enabled without a restart")
+	}
+
+	// The protocols served are fixed when the listener starts
+	if newCfg.HTTP2Enabled() != rs.cfg.HTTP2Enabled() || newCfg.H2C != rs.cfg.H2C {
+		log.Warn().Msg("http2 and h2c cannot be changed without a restart, keeping current protocols")
+	}
+
 	// Update log format and level
 	setupLogger(newCfg.LogFormat)
 	setupLogLevel(newCfg.LogLevel)
+	logLintIssues(newCfg)
 
 	// Create new server with updated config
 	newServer := server.New(server.Config{
@@ -84,10 +109,14 @@ func (rs *reloadableServer) reload() error {
 
 	// Update server atomically
 	rs.mu.Lock()
+	oldServer := rs.server
 	rs.server = newServer
 	rs.cfg = newCfg
 	rs.mu.Unlock()
 
+	// Release idle connections held by the previous server
+	oldServer.Close()
+
 	log.Info().Msg("Configuration reloaded successfully")
 	return nil
 }
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+//
+// This code is licensed under MIT license (see LICENSE for details)
+
+// Copyright (c) True Tickets, Inc.
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This file is part of API Aggregator.
+//
+// API Aggregator is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+// API Aggregator is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License along with API Aggregator. If not, see <https://www.gnu.org/licenses/>.
+package main
+
+import (
+	"crypto/tls"
+	"sync/atomic"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// tlsReloader serves the current listener TLS configuration. Reloading swaps the
+// configuration used for new handshakes while established connections are kept.
+type tlsReloader struct {
+	current atomic.Pointer[tls.Config]
+
+	// ALPN protocols, fixed at startup like the protocols the HTTP server handles
+	nextProtos []string
+}
+
+// newTLSReloader loads the listener certificates from the configuration
+func newTLSReloader(cfg *config.Config) (*tlsReloader, error) {
+	r := &tlsReloader{nextProtos: []string{"http/1.1"}}
+	if cfg.HTTP2Enabled() {
+		r.nextProtos = []string{"h2", "http/1.1"}
+	}
+	if err := r.reload(cfg); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload loads the certificates and TLS policy from the configuration
+func (r *tlsReloader) reload(cfg *config.Config) error {
+	tlsConfig, err := cfg.TLS.ServerConfig()
+	if err != nil {
+		return err
+	}
+
+	// ALPN must be set here because the base configuration is not consulted per handshake
+	tlsConfig.NextProtos = r.nextProtos
+
+	r.current.Store(tlsConfig)
+	return nil
+}
+
+// serverConfig returns the base TLS configuration for the HTTP server
+func (r *tlsReloader) serverConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/testcert"
+)
+
+func TestTLSReloader_KeepsStartupProtocols(t *testing.T) {
+	cert := testcert.Write(t, t.TempDir(), "server")
+	disabled := false
+	cfg := &config.Config{TLS: config.ServerTLS{CertFile: cert.CertFile, KeyFile: cert.KeyFile}}
+
+	certs, err := newTLSReloader(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"h2", "http/1.1"}, certs.current.Load().NextProtos)
+
+	// The HTTP server only handles the protocols it started with
+	cfg.HTTP2 = &disabled
+	require.NoError(t, certs.reload(cfg))
+	assert.Equal(t, []string{"h2", "http/1.1"}, certs.current.Load().NextProtos)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrueTickets/api-aggregator/internal/client"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+const (
+	// Headers set by HMAC signing alongside the signature header
+	timestampHeader     = "X-Timestamp"
+	contentDigestHeader = "X-Content-SHA256"
+)
+
+// Config holds authentication provider configuration
+type Config struct {
+	Auth config.Auth
+
+	// HTTP client used to request OAuth2 tokens (defaults to http.DefaultClient); requests
+	// are bounded by a timeout of their own
+	HTTPClient *http.Client
+}
+
+// New creates the authenticator for a backend auth configuration, resolving its secrets
+func New(cfg Config) (client.Authenticator, error) {
+	a := cfg.Auth
+
+	switch a.Type {
+	case "bearer":
+		token, err := a.Token.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		return &staticHeader{header: "Authorization", value: "Bearer " + token}, nil
+	case "api_key":
+		token, err := a.Token.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		return &staticHeader{header: a.Header, value: token}, nil
+	case "basic":
+		password, err := a.Password.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		request := &http.Request{Header: make(http.Header)}
+		request.SetBasicAuth(a.Username, password)
+		return &staticHeader{header: "Authorization", value: request.Header.Get("Authorization")}, nil
+	case "oauth2":
+		secret, err := a.ClientSecret.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		httpClient := cfg.HTTPClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		return &clientCredentials{
+			httpClient:   httpClient,
+			tokenURL:     a.TokenURL,
+			clientID:     a.ClientID,
+			clientSecret: secret,
+			scopes:       a.Scopes,
+			audience:     a.Audience,
+			now:          time.Now,
+		}, nil
+	case "hmac":
+		secret, err := a.Secret.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		return newHMACSigner(a, []byte(secret))
+	default:
+		return nil, fmt.Errorf("unsupported auth type %s", a.Type)
+	}
+}
+
+// staticHeader sets a fixed credential header
+type staticHeader struct {
+	header string
+	value  string
+}
+
+// Authenticate sets the credential header
+func (s *staticHeader) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set(s.header, s.value)
+	return nil
+}
+
+// Header returns the credential header name
+func (s *staticHeader) Header() string {
+	return s.header
+}
+
+// hmacSigner signs requests with a shared secret. The signature covers the method,
+// path and query, a Unix timestamp and the SHA-256 digest of the body, separated by newlines.
+type hmacSigner struct {
+	header    string
+	keyID     string
+	algorithm string
+	newHash   func() hash.Hash
+	secret    []byte
+	now       func() time.Time
+}
+
+// newHMACSigner creates a signer for the configured algorithm
+func newHMACSigner(a config.Auth, secret []byte) (*hmacSigner, error) {
+	signer := &hmacSigner{
+		header:    a.Header,
+		keyID:     a.KeyID,
+		algorithm: "hmac-" + a.Algorithm,
+		secret:    secret,
+		now:       time.Now,
+	}
+
+	switch a.Algorithm {
+	case "sha256":
+		signer.newHash = sha256.New
+	case "sha512":
+		signer.newHash = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported hmac algorithm %s", a.Algorithm)
+	}
+
+	return signer, nil
+}
+
+// Authenticate sets the timestamp, body digest and signature headers
+func (h *hmacSigner) Authenticate(_ context.Context, req *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(h.now().Unix(), 10)
+	digest := sha256.Sum256(body)
+	bodyDigest := hex.EncodeToString(digest[:])
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.RequestURI(),
+		timestamp,
+		bodyDigest,
+	}, "\n")
+
+	mac := hmac.New(h.newHash, h.secret)
+	mac.Write([]byte(canonical))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	value := fmt.Sprintf("algorithm=%s,signature=%s", h.algorithm, signature)
+	if h.keyID != "" {
+		value = fmt.Sprintf("keyId=%s,%s", h.keyID, value)
+	}
+
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(contentDigestHeader, bodyDigest)
+	req.Header.Set(h.header, value)
+	return nil
+}
+
+// Header returns the signature header name
+func (h *hmacSigner) Header() string {
+	return h.header
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestNew_StaticCredentials(t *testing.T) {
+	t.Setenv("TEST_BACKEND_TOKEN", "env-token")
+
+	secretFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-password\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		auth     config.Auth
+		header   string
+		expected string
+	}{
+		{
+			name:     "bearer from env",
+			auth:     config.Auth{Type: "bearer", Token: config.Secret{Env: "TEST_BACKEND_TOKEN"}},
+			header:   "Authorization",
+			expected: "Bearer env-token",
+		},
+		{
+			name:     "api key with custom header",
+			auth:     config.Auth{Type: "api_key", Token: config.Secret{Value: "key"}, Header: "X-Service-Key"},
+			header:   "X-Service-Key",
+			expected: "key",
+		},
+		{
+			name:     "basic with password from file",
+			auth:     config.Auth{Type: "basic", Username: "user", Password: config.Secret{File: secretFile}},
+			header:   "Authorization",
+			expected: "Basic dXNlcjpmaWxlLXBhc3N3b3Jk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := New(Config{Auth: tt.auth})
+			require.NoError(t, err)
+			assert.Equal(t, tt.header, provider.Header())
+
+			req := httptest.NewRequest(http.MethodGet, "http://backend/users", nil)
+			require.NoError(t, provider.Authenticate(context.Background(), req, nil))
+			assert.Equal(t, tt.expected, req.Header.Get(tt.header))
+		})
+	}
+}
+
+func TestNew_MissingSecret(t *testing.T) {
+	_, err := New(Config{Auth: config.Auth{Type: "bearer", Token: config.Secret{Env: "TEST_UNSET_TOKEN"}}})
+	assert.ErrorContains(t, err, "TEST_UNSET_TOKEN is not set")
+}
+
+func TestHMACSigner(t *testing.T) {
+	provider, err := New(Config{Auth: config.Auth{
+		Type:      "hmac",
+		KeyID:     "aggregator",
+		Secret:    config.Secret{Value: "shared-secret"},
+		Header:    "X-Signature",
+		Algorithm: "sha256",
+	}})
+	require.NoError(t, err)
+
+	signer := provider.(*hmacSigner)
+	signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	body := []byte(`{"id":1}`)
+	req := httptest.NewRequest(http.MethodPost, "http://backend/orders?expand=items", nil)
+	require.NoError(t, signer.Authenticate(context.Background(), req, body))
+
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte("shared-secret"))
+	mac.Write([]byte("POST\n/orders?expand=items\n1700000000\n" + hex.EncodeToString(digest[:])))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, "1700000000", req.Header.Get("X-Timestamp"))
+	assert.Equal(t, hex.EncodeToString(digest[:]), req.Header.Get("X-Content-SHA256"))
+	assert.Equal(t, "keyId=aggregator,algorithm=hmac-sha256,signature="+signature, req.Header.Get("X-Signature"))
+}
+
+func TestClientCredentials(t *testing.T) {
+	var requests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "aggregator", clientID)
+		assert.Equal(t, "s3cret", clientSecret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "users:read users:write", r.Form.Get("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	provider, err := New(Config{Auth: config.Auth{
+		Type:         "oauth2",
+		TokenURL:     tokenServer.URL,
+		ClientID:     "aggregator",
+		ClientSecret: config.Secret{Value: "s3cret"},
+		Scopes:       []string{"users:read", "users:write"},
+	}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	credentials := provider.(*clientCredentials)
+	credentials.now = func() time.Time { return now }
+
+	authenticate := func() string {
+		req := httptest.NewRequest(http.MethodGet, "http://backend/users", nil)
+		require.NoError(t, provider.Authenticate(context.Background(), req, nil))
+		return req.Header.Get("Authorization")
+	}
+
+	// The token is cached until shortly before it expires
+	assert.Equal(t, "Bearer token-1", authenticate())
+	assert.Equal(t, "Bearer token-1", authenticate())
+	assert.Equal(t, int32(1), requests.Load())
+
+	now = now.Add(time.Hour - 10*time.Second)
+	assert.Equal(t, "Bearer token-2", authenticate())
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestClientCredentials_ShortLifetime(t *testing.T) {
+	var requests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := requests.Add(1)
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":20}`, n)
+	}))
+	defer tokenServer.Close()
+
+	authenticator, err := New(Config{Auth: config.Auth{
+		Type:         "oauth2",
+		TokenURL:     tokenServer.URL,
+		ClientID:     "aggregator",
+		ClientSecret: config.Secret{Value: "s3cret"},
+	}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	credentials := authenticator.(*clientCredentials)
+	credentials.now = func() time.Time { return now }
+
+	// The leeway is capped at a quarter of the lifetime, so the token is still reused
+	token, err := credentials.currentToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	now = now.Add(14 * time.Second)
+	token, err = credentials.currentToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	now = now.Add(2 * time.Second)
+	token, err = credentials.currentToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+}
+
+func TestClientCredentials_SharedRefresh(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		<-release
+		_, _ = fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	authenticator, err := New(Config{Auth: config.Auth{
+		Type:         "oauth2",
+		TokenURL:     tokenServer.URL,
+		ClientID:     "aggregator",
+		ClientSecret: config.Secret{Value: "s3cret"},
+	}})
+	require.NoError(t, err)
+	credentials := authenticator.(*clientCredentials)
+
+	// A caller giving up does not wait for the slow token endpoint
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = credentials.currentToken(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Waiting callers share the request in flight
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = credentials.currentToken(context.Background())
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"token", "token", "token", "token", "token"}, tokens)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientCredentials_Error(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer tokenServer.Close()
+
+	provider, err := New(Config{Auth: config.Auth{
+		Type:         "oauth2",
+		TokenURL:     tokenServer.URL,
+		ClientID:     "aggregator",
+		ClientSecret: config.Secret{Value: "s3cret"},
+	}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://backend/users", nil)
+	err = provider.Authenticate(context.Background(), req, nil)
+	assert.ErrorContains(t, err, "token endpoint returned status 401")
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
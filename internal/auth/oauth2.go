@@ -0,0 +1,175 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Tokens are refreshed this long before they expire, or a quarter of their lifetime if shorter
+	tokenExpiryLeeway = 30 * time.Second
+
+	// Maximum time for a token request, which callers share rather than bound with their own deadline
+	tokenRequestTimeout = 10 * time.Second
+
+	// Lifetime assumed for tokens returned without expires_in
+	defaultTokenLifetime = 5 * time.Minute
+
+	// Maximum size of a token response
+	maxTokenResponseBytes = 1 << 20
+)
+
+// tokenResponse is the token endpoint response body
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// clientCredentials authenticates with an OAuth2 client credentials grant.
+// Tokens are cached and refreshed shortly before they expire.
+type clientCredentials struct {
+	httpClient   *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	audience     string
+	now          func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+
+	// Token request in flight, nil if none
+	refresh *tokenRefresh
+}
+
+// tokenRefresh is a token request shared by the callers waiting for it
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// Authenticate sets a bearer token, requesting a new one if the cached token is expiring
+func (c *clientCredentials) Authenticate(ctx context.Context, req *http.Request, _ []byte) error {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Header returns the credential header name
+func (c *clientCredentials) Header() string {
+	return "Authorization"
+}
+
+// currentToken returns the cached token or waits for a new one. Concurrent callers share a
+// single token request, and the lock is not held while it runs, so a slow token endpoint only
+// delays the callers that need a new token.
+func (c *clientCredentials) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.token != "" && c.now().Before(c.refreshAt) {
+		token := c.token
+		c.mu.Unlock()
+		return token, nil
+	}
+	refresh := c.refresh
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		c.refresh = refresh
+		go c.refreshToken(context.WithoutCancel(ctx), refresh)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refreshToken requests a new token for the waiting callers. The request is not cancelled
+// with the caller that started it, as other callers may be waiting for it.
+func (c *clientCredentials) refreshToken(ctx context.Context, refresh *tokenRefresh) {
+	ctx, cancel := context.WithTimeout(ctx, tokenRequestTimeout)
+	defer cancel()
+
+	token, lifetime, err := c.requestToken(ctx)
+
+	c.mu.Lock()
+	if err == nil {
+		c.token = token
+		c.refreshAt = c.now().Add(lifetime - min(tokenExpiryLeeway, lifetime/4))
+	}
+	c.refresh = nil
+	c.mu.Unlock()
+
+	refresh.token, refresh.err = token, err
+	close(refresh.done)
+}
+
+// requestToken requests a token from the token endpoint
+func (c *clientCredentials) requestToken(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+	if c.audience != "" {
+		form.Set("audience", c.audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseBytes))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	// The response body is not included as it may echo credentials
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+
+	lifetime := defaultTokenLifetime
+	if token.ExpiresIn > 0 {
+		lifetime = time.Duration(token.ExpiresIn) * time.Second
+	}
+	return token.AccessToken, lifetime, nil
+}
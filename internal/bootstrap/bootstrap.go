@@ -0,0 +1,476 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package bootstrap generates a starter configuration from the OpenAPI documents of upstream services.
+package bootstrap
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods are the operations mapped from a path item, in output order
+var methods = []string{"get", "head", "post", "put", "patch", "delete", "options"}
+
+// pathParamPattern matches a path parameter spanning a whole path segment
+var pathParamPattern = regexp.MustCompile(`^\{([^{}]+)\}$`)
+
+// nonWordPattern matches the characters replaced in service and parameter names
+var nonWordPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Spec is the OpenAPI document of an upstream service
+type Spec struct {
+	// Service name, used as endpoint path prefix, backend name and group
+	Service string
+
+	// Decoded OpenAPI document
+	Document map[string]interface{}
+}
+
+// Endpoint is a generated endpoint
+type Endpoint struct {
+	Endpoint string    `yaml:"endpoint"`
+	Method   string    `yaml:"method"`
+	Summary  string    `yaml:"summary,omitempty"`
+	Tags     []string  `yaml:"tags,omitempty"`
+	Backends []Backend `yaml:"backends"`
+
+	// Things to check by hand, written as comments
+	notes []string
+}
+
+// Backend is a generated backend
+type Backend struct {
+	Name       string   `yaml:"name"`
+	URLPattern string   `yaml:"url_pattern"`
+	Host       string   `yaml:"host"`
+	Encoding   string   `yaml:"encoding,omitempty"`
+	Group      string   `yaml:"group"`
+	Allow      []string `yaml:"allow,omitempty"`
+}
+
+// Note flags an operation, or a whole service, that needs attention
+type Note struct {
+	Service string
+
+	// Operation as "METHOD path", empty for notes about the whole service
+	Operation string
+
+	Message string
+
+	// Whether the operation was left out of the configuration
+	Skipped bool
+}
+
+// String formats the note for display
+func (n Note) String() string {
+	var b strings.Builder
+	b.WriteString(n.Service)
+	if n.Operation != "" {
+		b.WriteString(": " + n.Operation)
+	}
+	if n.Skipped {
+		b.WriteString(": skipped")
+	}
+	b.WriteString(": " + n.Message)
+	return b.String()
+}
+
+// Result is a generated configuration
+type Result struct {
+	Endpoints []Endpoint
+	Notes     []Note
+}
+
+// LoadSpec reads an OpenAPI 3 document in JSON or YAML. The service is named after
+// the document title, or the file name if it has none.
+func LoadSpec(path string) (Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Spec files are provided by the operator
+	if err != nil {
+		return Spec{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Spec{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	version, _ := document["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return Spec{}, fmt.Errorf("%s: only OpenAPI 3 documents are supported", path)
+	}
+
+	name := serviceName(stringAt(document, "info", "title"))
+	if name == "" {
+		name = serviceName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+	return Spec{Service: name, Document: document}, nil
+}
+
+// Generate maps the operations of the specs to endpoints with one backend each, prefixed
+// with the service name. Services with the same name are numbered.
+func Generate(specs []Spec) Result {
+	var result Result
+	seen := make(map[string]int)
+
+	for _, spec := range specs {
+		service := spec.Service
+		seen[service]++
+		if seen[service] > 1 {
+			service += "-" + strconv.Itoa(seen[service])
+		}
+		generateService(&result, service, spec.Document)
+	}
+	return result
+}
+
+// generateService maps the operations of one service
+func generateService(result *Result, service string, document map[string]interface{}) {
+	host, prefix, _ := serverURL(document["servers"])
+	if host == "" {
+		host = "http://" + service
+		result.Notes = append(result.Notes, Note{
+			Service: service,
+			Message: "no absolute server URL, replace the host " + host,
+		})
+	}
+
+	paths, _ := document["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range methods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := strings.ToUpper(method) + " " + path
+
+			endpoint, notes, err := mapOperation(document, service, host, prefix, path, method, item, operation)
+			if err != nil {
+				result.Notes = append(result.Notes, Note{Service: service, Operation: name, Message: err.Error(), Skipped: true})
+				continue
+			}
+			for _, message := range notes {
+				result.Notes = append(result.Notes, Note{Service: service, Operation: name, Message: message})
+			}
+			result.Endpoints = append(result.Endpoints, endpoint)
+		}
+		if _, ok := item["trace"]; ok {
+			result.Notes = append(result.Notes, Note{
+				Service:   service,
+				Operation: "TRACE " + path,
+				Message:   "method TRACE is not supported",
+				Skipped:   true,
+			})
+		}
+	}
+}
+
+// mapOperation maps an operation to an endpoint, returning an error if it cannot be mapped
+func mapOperation(
+	document map[string]interface{},
+	service, host, prefix, path, method string,
+	item, operation map[string]interface{},
+) (Endpoint, []string, error) {
+	mappedPath, err := mapPath(path)
+	if err != nil {
+		return Endpoint{}, nil, err
+	}
+
+	backend := Backend{
+		Name:       service,
+		URLPattern: prefix + mappedPath,
+		Host:       host,
+		Group:      service,
+	}
+	for _, servers := range []interface{}{operation["servers"], item["servers"]} {
+		if serverHost, serverPrefix, ok := serverURL(servers); ok {
+			if serverHost != "" {
+				backend.Host = serverHost
+			}
+			backend.URLPattern = serverPrefix + mappedPath
+			break
+		}
+	}
+
+	var notes []string
+	response := successResponse(document, operation)
+	if content, ok := response["content"].(map[string]interface{}); ok && len(content) > 0 {
+		mediaType, encoding := responseMediaType(content)
+		if mediaType == "" {
+			return Endpoint{}, nil, fmt.Errorf("response media types %s are not supported",
+				strings.Join(sortedKeys(content), ", "))
+		}
+		if encoding != "json" {
+			backend.Encoding = encoding
+		}
+		media, _ := content[mediaType].(map[string]interface{})
+		backend.Allow = schemaProperties(document, media["schema"], 0)
+	}
+
+	if query := queryParams(document, item, operation); len(query) > 0 {
+		notes = append(notes, "query parameters "+strings.Join(query, ", ")+" are not forwarded to the backend")
+	}
+	if requiresAuth(document, operation) {
+		notes = append(notes, "requires authentication, forward the caller credentials or configure backend auth")
+	}
+
+	endpoint := Endpoint{
+		Endpoint: "/" + service + mappedPath,
+		Method:   strings.ToUpper(method),
+		Summary:  stringAt(operation, "summary"),
+		Tags:     stringList(operation["tags"]),
+		Backends: []Backend{backend},
+		notes:    notes,
+	}
+	return endpoint, notes, nil
+}
+
+// mapPath converts an OpenAPI path to the path used in endpoints and URL patterns. Parameter
+// names are made valid route parameter names; parameters must span whole path segments.
+func mapPath(path string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		match := pathParamPattern.FindStringSubmatch(segment)
+		if match == nil {
+			if strings.ContainsAny(segment, "{}") {
+				return "", fmt.Errorf("path parameters must span whole path segments")
+			}
+			continue
+		}
+		segments[i] = "{" + nonWordPattern.ReplaceAllString(match[1], "_") + "}"
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// serverURL splits the first server URL into the host and the path prefix of backend URL patterns,
+// returning false if there is no server. The host is empty for relative URLs. Server variables
+// take their default values.
+func serverURL(servers interface{}) (string, string, bool) {
+	list, _ := servers.([]interface{})
+	if len(list) == 0 {
+		return "", "", false
+	}
+	server, _ := list[0].(map[string]interface{})
+	raw, _ := server["url"].(string)
+
+	variables, _ := server["variables"].(map[string]interface{})
+	for name, variable := range variables {
+		value, _ := variable.(map[string]interface{})
+		raw = strings.ReplaceAll(raw, "{"+name+"}", fmt.Sprint(value["default"]))
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", "", false
+	}
+	prefix := strings.TrimSuffix(parsed.Path, "/")
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", prefix, true
+	}
+	return parsed.Scheme + "://" + parsed.Host, prefix, true
+}
+
+// successResponse returns the documented successful response of an operation: 200,
+// then the lowest 2xx code, then 2XX, then default
+func successResponse(document, operation map[string]interface{}) map[string]interface{} {
+	responses, _ := operation["responses"].(map[string]interface{})
+
+	var codes []string
+	for code := range responses {
+		if len(code) == 3 && code[0] == '2' && code != "2XX" {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	codes = append(codes, "2XX", "default")
+
+	for _, code := range codes {
+		if response, ok := resolve(document, responses[code]).(map[string]interface{}); ok {
+			return response
+		}
+	}
+	return nil
+}
+
+// responseMediaType picks the media type of a response the backend can decode, with its encoding
+func responseMediaType(content map[string]interface{}) (string, string) {
+	for _, mediaType := range sortedKeys(content) {
+		base := strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+		switch {
+		case base == "application/json" || strings.HasSuffix(base, "+json"):
+			return mediaType, "json"
+		case base == "application/xml" || base == "text/xml" || strings.HasSuffix(base, "+xml"):
+			return mediaType, "xml"
+		case base == "application/yaml" || base == "application/x-yaml" || base == "text/yaml":
+			return mediaType, "yaml"
+		}
+	}
+	return "", ""
+}
+
+// schemaProperties proposes an allow list from the top-level properties of a response schema,
+// or of its items for arrays. It is empty if the schema does not name its properties.
+func schemaProperties(document map[string]interface{}, schema interface{}, depth int) []string {
+	// Guard against recursive references
+	if depth > 10 {
+		return nil
+	}
+	object, _ := resolve(document, schema).(map[string]interface{})
+	if object == nil {
+		return nil
+	}
+	if items, ok := object["items"]; ok {
+		return schemaProperties(document, items, depth+1)
+	}
+
+	names := make(map[string]bool)
+	properties, _ := object["properties"].(map[string]interface{})
+	for name := range properties {
+		names[name] = true
+	}
+	composed, _ := object["allOf"].([]interface{})
+	for _, part := range composed {
+		for _, name := range schemaProperties(document, part, depth+1) {
+			names[name] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return sortedKeys(names)
+}
+
+// queryParams returns the sorted names of the query parameters of an operation
+func queryParams(document, item, operation map[string]interface{}) []string {
+	names := make(map[string]bool)
+	for _, source := range []interface{}{item["parameters"], operation["parameters"]} {
+		params, _ := source.([]interface{})
+		for _, param := range params {
+			object, _ := resolve(document, param).(map[string]interface{})
+			if object["in"] == "query" {
+				if name, ok := object["name"].(string); ok {
+					names[name] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(names)
+}
+
+// requiresAuth reports whether an operation, or the document by default, requires credentials
+func requiresAuth(document, operation map[string]interface{}) bool {
+	security, ok := operation["security"]
+	if !ok {
+		security = document["security"]
+	}
+	requirements, _ := security.([]interface{})
+	for _, requirement := range requirements {
+		// An empty requirement makes authentication optional
+		if schemes, ok := requirement.(map[string]interface{}); ok && len(schemes) == 0 {
+			return false
+		}
+	}
+	return len(requirements) > 0
+}
+
+// resolve follows a local $ref, returning the value unchanged if it is not a reference
+func resolve(document map[string]interface{}, value interface{}) interface{} {
+	for range 10 {
+		object, _ := value.(map[string]interface{})
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return value
+		}
+		pointer, local := strings.CutPrefix(ref, "#/")
+		if !local {
+			return nil
+		}
+
+		var current interface{} = document
+		for _, token := range strings.Split(pointer, "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			parent, _ := current.(map[string]interface{})
+			current = parent[token]
+		}
+		value = current
+	}
+	return nil
+}
+
+// serviceName turns a title into a lower-case name usable as a path segment
+func serviceName(title string) string {
+	name := nonWordPattern.ReplaceAllString(strings.ToLower(title), "-")
+	name = strings.ReplaceAll(name, "_", "-")
+	return strings.Trim(name, "-")
+}
+
+// stringAt returns the string at a path of nested objects, or an empty string
+func stringAt(object map[string]interface{}, keys ...string) string {
+	var current interface{} = object
+	for _, key := range keys {
+		parent, _ := current.(map[string]interface{})
+		current = parent[key]
+	}
+	value, _ := current.(string)
+	return value
+}
+
+// stringList returns the strings of a list
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var list []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// YAML encodes the result as a configuration file. Notes about an endpoint are written as
+// comments above it, and skipped operations and service notes at the top of the file.
+func (r Result) YAML() ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(struct {
+		Endpoints []Endpoint `yaml:"endpoints"`
+	}{Endpoints: r.Endpoints}); err != nil {
+		return nil, err
+	}
+
+	var header []string
+	for _, note := range r.Notes {
+		if note.Operation == "" || note.Skipped {
+			header = append(header, "TODO "+note.String())
+		}
+	}
+	root.HeadComment = strings.Join(append([]string{"Generated from upstream OpenAPI documents"}, header...), "\n")
+
+	items := root.Content[1].Content
+	for i, endpoint := range r.Endpoints {
+		var lines []string
+		for _, note := range endpoint.notes {
+			lines = append(lines, "TODO "+note)
+		}
+		items[i].HeadComment = strings.Join(lines, "\n")
+	}
+
+	return yaml.Marshal(&root)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestGenerate(t *testing.T) {
+	users, err := LoadSpec("testdata/users.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "users-service", users.Service)
+
+	orders, err := LoadSpec("testdata/orders.json")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", orders.Service, "untitled services are named after the file")
+
+	result := Generate([]Spec{users, orders})
+
+	assert.Equal(t, []Endpoint{
+		{
+			Endpoint: "/users-service/users/{user_id}",
+			Method:   "GET",
+			Summary:  "Get a user",
+			Tags:     []string{"users"},
+			Backends: []Backend{{
+				Name:       "users-service",
+				URLPattern: "/v1/users/{user_id}",
+				Host:       "https://api.users.example.com",
+				Group:      "users-service",
+				Allow:      []string{"email", "id", "name"},
+			}},
+			notes: []string{
+				"query parameters expand are not forwarded to the backend",
+				"requires authentication, forward the caller credentials or configure backend auth",
+			},
+		},
+		{
+			Endpoint: "/users-service/users/{user_id}",
+			Method:   "DELETE",
+			Backends: []Backend{{
+				Name:       "users-service",
+				URLPattern: "/v1/users/{user_id}",
+				Host:       "https://api.users.example.com",
+				Group:      "users-service",
+			}},
+		},
+		{
+			Endpoint: "/orders/orders",
+			Method:   "GET",
+			Backends: []Backend{{
+				Name:       "orders",
+				URLPattern: "/api/orders",
+				Host:       "http://orders",
+				Encoding:   "xml",
+				Group:      "orders",
+				Allow:      []string{"id", "total"},
+			}},
+		},
+		{
+			Endpoint: "/orders/orders",
+			Method:   "POST",
+			Backends: []Backend{{
+				Name:       "orders",
+				URLPattern: "/api/orders",
+				Host:       "http://orders",
+				Group:      "orders",
+			}},
+		},
+	}, result.Endpoints)
+
+	var skipped []string
+	for _, note := range result.Notes {
+		if note.Skipped {
+			skipped = append(skipped, note.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"users-service: GET /avatars/{id}: skipped: response media types image/png are not supported",
+		"users-service: GET /files/{name}.{ext}: skipped: path parameters must span whole path segments",
+	}, skipped)
+	assert.Contains(t, result.Notes, Note{Service: "orders", Message: "no absolute server URL, replace the host http://orders"})
+}
+
+func TestGenerate_DuplicateServices(t *testing.T) {
+	spec := Spec{
+		Service: "users",
+		Document: map[string]interface{}{
+			"servers": []interface{}{map[string]interface{}{"url": "http://users"}},
+			"paths": map[string]interface{}{
+				"/users": map[string]interface{}{"get": map[string]interface{}{}},
+			},
+		},
+	}
+
+	result := Generate([]Spec{spec, spec})
+
+	require.Len(t, result.Endpoints, 2)
+	assert.Equal(t, "/users/users", result.Endpoints[0].Endpoint)
+	assert.Equal(t, "/users-2/users", result.Endpoints[1].Endpoint)
+}
+
+func TestLoadSpec_Errors(t *testing.T) {
+	dir := t.TempDir()
+	swagger := filepath.Join(dir, "swagger.yaml")
+	require.NoError(t, os.WriteFile(swagger, []byte("swagger: \"2.0\"\n"), 0o600))
+
+	_, err := LoadSpec(swagger)
+	assert.ErrorContains(t, err, "only OpenAPI 3 documents are supported")
+
+	_, err = LoadSpec(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestResult_YAML(t *testing.T) {
+	users, err := LoadSpec("testdata/users.yaml")
+	require.NoError(t, err)
+	orders, err := LoadSpec("testdata/orders.json")
+	require.NoError(t, err)
+
+	data, err := Generate([]Spec{users, orders}).YAML()
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "# TODO users-service: GET /files/{name}.{ext}: skipped")
+	assert.Contains(t, string(data), "# TODO query parameters expand are not forwarded to the backend")
+
+	// The generated configuration loads as is
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, data, 0o600))
+	cfg, err := config.LoadConfig(configFile)
+	require.NoError(t, err)
+	require.Len(t, cfg.Endpoints, 4)
+	assert.Equal(t, "users-service", cfg.Endpoints[0].Backends[0].Group)
+}
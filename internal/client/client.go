@@ -11,10 +11,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/yaml.v3"
+
+	"github.com/TrueTickets/api-aggregator/internal/schema"
 )
 
 const (
@@ -23,8 +27,29 @@ const (
 	encodingJSON = "json"
 	encodingXML  = "xml"
 	encodingYAML = "yaml"
+
+	// Replacement for credential header values in logs
+	redactedValue = "[REDACTED]"
 )
 
+// sensitiveHeaders are never logged; forwarded caller headers may carry credentials too
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-API-Key",
+	"X-Auth-Token",
+}
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	// Authenticate adds credentials to the request; body is the request body, if any
+	Authenticate(ctx context.Context, req *http.Request, body []byte) error
+
+	// Header returns the name of the header carrying the credentials
+	Header() string
+}
+
 // Client handles HTTP requests to backend services
 type Client struct {
 	httpClient *http.Client
@@ -44,8 +69,45 @@ type RequestConfig struct {
 	Method   string
 	URL      string
 	Encoding string
-	Headers  map[string]string
+	Headers  http.Header
 	Body     io.Reader
+
+	// Transport overrides the HTTP client transport for this request (optional)
+	Transport http.RoundTripper
+
+	// Auth adds backend credentials after the request is logged (optional)
+	Auth Authenticator
+
+	// ResponseSchema validates the decoded response (optional)
+	ResponseSchema *schema.Schema
+
+	// EnforceResponseSchema fails the request if the response does not match the schema;
+	// otherwise mismatches are logged and returned with the response
+	EnforceResponseSchema bool
+}
+
+// Response holds a decoded backend response and its metadata
+type Response struct {
+	Data       interface{}
+	StatusCode int
+	Header     http.Header
+
+	// SchemaErrors lists how the response does not match a schema that is not enforced
+	SchemaErrors []schema.Error
+}
+
+// SchemaError reports a response that does not match an enforced schema
+type SchemaError struct {
+	Errors []schema.Error
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	message := "response does not match schema: " + e.Errors[0].Error()
+	if len(e.Errors) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(e.Errors)-1)
+	}
+	return message
 }
 
 // New creates a new client instance
@@ -59,6 +121,15 @@ func New(cfg Config) *Client {
 
 // Request makes an HTTP request and returns the parsed response
 func (c *Client) Request(ctx context.Context, cfg RequestConfig) (interface{}, error) {
+	resp, err := c.Do(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Do makes an HTTP request and returns the parsed response with its status and headers
+func (c *Client) Do(ctx context.Context, cfg RequestConfig) (*Response, error) {
 	ctx, span := c.tracer.Start(ctx, fmt.Sprintf("backend_request_%s", cfg.URL))
 	defer span.End()
 
@@ -79,6 +150,13 @@ func (c *Client) Request(ctx context.Context, cfg RequestConfig) (interface{}, e
 		return nil, err
 	}
 
+	// Add backend credentials
+	if cfg.Auth != nil {
+		if err := cfg.Auth.Authenticate(ctx, req, bodyBytes); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
 	// Make request and handle response
 	return c.makeRequestAndHandleResponse(req, cfg)
 }
@@ -110,16 +188,36 @@ func (c *Client) logOutgoingRequest(cfg RequestConfig, bodyBytes []byte) {
 		Str("encoding", cfg.Encoding)
 
 	if len(cfg.Headers) > 0 {
-		logEvent = logEvent.Interface("headers", cfg.Headers)
+		logEvent = logEvent.Interface("headers", redactHeaders(cfg.Headers, cfg.Auth))
 	}
 
 	if len(bodyBytes) > 0 {
-		logEvent = logEvent.RawJSON("body", bodyBytes)
+		logEvent = logBody(logEvent, "body", bodyBytes)
 	}
 
 	logEvent.Msg("outgoing backend request")
 }
 
+// redactHeaders returns a copy of the headers with credential values replaced
+func redactHeaders(headers http.Header, auth Authenticator) http.Header {
+	sensitive := sensitiveHeaders
+	if auth != nil {
+		sensitive = append([]string{auth.Header()}, sensitive...)
+	}
+
+	redacted := make(http.Header, len(headers))
+	for key, values := range headers {
+		redacted[key] = values
+		for _, name := range sensitive {
+			if strings.EqualFold(key, name) {
+				redacted[key] = []string{redactedValue}
+				break
+			}
+		}
+	}
+	return redacted
+}
+
 // createHTTPRequest creates and configures the HTTP request
 func (c *Client) createHTTPRequest(ctx context.Context, cfg RequestConfig) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, cfg.Body)
@@ -127,9 +225,12 @@ func (c *Client) createHTTPRequest(ctx context.Context, cfg RequestConfig) (*htt
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set custom headers
-	for key, value := range cfg.Headers {
-		req.Header.Set(key, value)
+	// Set custom headers, keeping all values of multi-valued headers
+	for key, values := range cfg.Headers {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
 	}
 
 	// Set Accept header based on encoding
@@ -168,8 +269,8 @@ func (c *Client) setContentTypeHeader(req *http.Request, encoding string) {
 }
 
 // makeRequestAndHandleResponse executes the HTTP request and processes the response
-func (c *Client) makeRequestAndHandleResponse(req *http.Request, cfg RequestConfig) (interface{}, error) {
-	resp, err := c.httpClient.Do(req)
+func (c *Client) makeRequestAndHandleResponse(req *http.Request, cfg RequestConfig) (*Response, error) {
+	resp, err := c.httpClientFor(cfg).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -196,7 +297,68 @@ func (c *Client) makeRequestAndHandleResponse(req *http.Request, cfg RequestConf
 	}
 
 	// Parse and return response
-	return c.parseResponse(body, cfg.Encoding)
+	data, err := c.parseResponse(body, cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	response := &Response{Data: data, StatusCode: resp.StatusCode, Header: resp.Header}
+
+	if cfg.ResponseSchema != nil {
+		if err := c.checkResponseSchema(req.Context(), cfg, response); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// checkResponseSchema validates a decoded response, recording mismatches on the request span.
+// Mismatches fail the request if the schema is enforced and are logged otherwise.
+func (c *Client) checkResponseSchema(ctx context.Context, cfg RequestConfig, resp *Response) error {
+	errs := cfg.ResponseSchema.Validate(resp.Data)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("response_schema_mismatch", trace.WithAttributes(
+		attribute.Int("errors", len(errs)),
+		attribute.String("first_error", errs[0].Error()),
+		attribute.Bool("enforced", cfg.EnforceResponseSchema),
+	))
+
+	if cfg.EnforceResponseSchema {
+		return &SchemaError{Errors: errs}
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	c.logger.Warn().
+		Str("method", cfg.Method).
+		Str("url", cfg.URL).
+		Strs("schema_errors", messages).
+		Msg("Backend response does not match schema")
+
+	resp.SchemaErrors = errs
+	return nil
+}
+
+// httpClientFor returns the HTTP client to use for a request, applying any transport override
+func (c *Client) httpClientFor(cfg RequestConfig) *http.Client {
+	if cfg.Transport == nil {
+		return c.httpClient
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = cfg.Transport
+	return &httpClient
+}
+
+// logBody adds a body to a log event, embedded if it is JSON and as a string otherwise
+func logBody(event *zerolog.Event, key string, body []byte) *zerolog.Event {
+	if json.Valid(body) {
+		return event.RawJSON(key, body)
+	}
+	return event.Bytes(key, body)
 }
 
 // logBackendResponse logs trace information for backend responses
@@ -208,7 +370,7 @@ func (c *Client) logBackendResponse(cfg RequestConfig, resp *http.Response, body
 		Interface("response_headers", resp.Header)
 
 	if len(body) > 0 {
-		logEvent = logEvent.RawJSON("response_body", body)
+		logEvent = logBody(logEvent, "response_body", body)
 	}
 
 	logEvent.Msg("backend response received")
@@ -253,7 +415,7 @@ func (c *Client) parseResponse(body []byte, encoding string) (interface{}, error
 }
 
 // Get makes a GET request
-func (c *Client) Get(ctx context.Context, url, encoding string, headers map[string]string) (interface{}, error) {
+func (c *Client) Get(ctx context.Context, url, encoding string, headers http.Header) (interface{}, error) {
 	return c.Request(ctx, RequestConfig{
 		Method:   "GET",
 		URL:      url,
@@ -263,7 +425,7 @@ func (c *Client) Get(ctx context.Context, url, encoding string, headers map[stri
 }
 
 // Post makes a POST request
-func (c *Client) Post(ctx context.Context, url, encoding string, headers map[string]string,
+func (c *Client) Post(ctx context.Context, url, encoding string, headers http.Header,
 	body io.Reader) (interface{}, error) {
 	return c.Request(ctx, RequestConfig{
 		Method:   "POST",
@@ -275,7 +437,7 @@ func (c *Client) Post(ctx context.Context, url, encoding string, headers map[str
 }
 
 // Put makes a PUT request
-func (c *Client) Put(ctx context.Context, url, encoding string, headers map[string]string,
+func (c *Client) Put(ctx context.Context, url, encoding string, headers http.Header,
 	body io.Reader) (interface{}, error) {
 	return c.Request(ctx, RequestConfig{
 		Method:   "PUT",
@@ -287,7 +449,7 @@ func (c *Client) Put(ctx context.Context, url, encoding string, headers map[stri
 }
 
 // Delete makes a DELETE request
-func (c *Client) Delete(ctx context.Context, url, encoding string, headers map[string]string) (interface{}, error) {
+func (c *Client) Delete(ctx context.Context, url, encoding string, headers http.Header) (interface{}, error) {
 	return c.Request(ctx, RequestConfig{
 		Method:   "DELETE",
 		URL:      url,
@@ -6,13 +6,18 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/schema"
 )
 
 func TestClient_Request_Logging(t *testing.T) {
@@ -84,3 +89,165 @@ func TestClient_Request_Logging(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Request_LogsXMLBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write([]byte(`<user><id>7</id></user>`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	var logBuf bytes.Buffer
+	client := New(Config{
+		HTTPClient: &http.Client{},
+		Tracer:     noop.NewTracerProvider().Tracer("test"),
+		Logger:     zerolog.New(&logBuf).Level(zerolog.TraceLevel),
+	})
+
+	_, err := client.Request(context.Background(), RequestConfig{
+		Method:   http.MethodPost,
+		URL:      server.URL,
+		Encoding: "xml",
+		Body:     strings.NewReader(`<createUser><name>Ada</name></createUser>`),
+	})
+	require.NoError(t, err)
+
+	// Every log line stays valid JSON, with the bodies as strings
+	lines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	require.Len(t, lines, 2)
+	var request, response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &request))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &response))
+	assert.Equal(t, "<createUser><name>Ada</name></createUser>", request["body"])
+	assert.Equal(t, "<user><id>7</id></user>", response["response_body"])
+}
+
+// headerAuth sets a fixed credential header for tests
+type headerAuth struct {
+	header string
+	value  string
+}
+
+func (a headerAuth) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set(a.header, a.value)
+	return nil
+}
+
+func (a headerAuth) Header() string {
+	return a.header
+}
+
+func TestClient_Request_Auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "backend-key", r.Header.Get("X-Service-Key"))
+		assert.Equal(t, "Bearer caller-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"ok": true}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	var logBuf bytes.Buffer
+	client := New(Config{
+		HTTPClient: &http.Client{},
+		Tracer:     noop.NewTracerProvider().Tracer("test"),
+		Logger:     zerolog.New(&logBuf).Level(zerolog.DebugLevel),
+	})
+
+	_, err := client.Request(context.Background(), RequestConfig{
+		Method:   http.MethodGet,
+		URL:      server.URL,
+		Encoding: "json",
+		Headers: http.Header{
+			"Authorization": {"Bearer caller-token"},
+			"X-Service-Key": {"caller-key"},
+			"X-Request-Id":  {"abc"},
+		},
+		Auth: headerAuth{header: "X-Service-Key", value: "backend-key"},
+	})
+	assert.NoError(t, err)
+
+	// Credentials never reach the debug log
+	logOutput := logBuf.String()
+	assert.Contains(t, logOutput, "abc")
+	assert.Contains(t, logOutput, "[REDACTED]")
+	assert.NotContains(t, logOutput, "caller-token")
+	assert.NotContains(t, logOutput, "caller-key")
+	assert.NotContains(t, logOutput, "backend-key")
+}
+
+func TestClient_Do_ResponseMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		HTTPClient: &http.Client{},
+		Tracer:     noop.NewTracerProvider().Tracer("test"),
+		Logger:     zerolog.Nop(),
+	})
+
+	resp, err := client.Do(context.Background(), RequestConfig{
+		Method:   http.MethodPost,
+		URL:      server.URL,
+		Encoding: "json",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, resp.Data)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+	assert.Equal(t, []string{"a=1", "b=2"}, resp.Header.Values("Set-Cookie"))
+}
+
+func TestClient_Do_ResponseSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"id": "1", "name": 2}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	responseSchema, err := schema.Compile(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":   map[string]interface{}{"type": "integer"},
+			"name": map[string]interface{}{"type": "string"},
+		},
+	})
+	require.NoError(t, err)
+
+	client := New(Config{
+		HTTPClient: &http.Client{},
+		Tracer:     noop.NewTracerProvider().Tracer("test"),
+		Logger:     zerolog.Nop(),
+	})
+
+	cfg := RequestConfig{
+		Method:                http.MethodGet,
+		URL:                   server.URL,
+		Encoding:              "json",
+		ResponseSchema:        responseSchema,
+		EnforceResponseSchema: true,
+	}
+
+	_, err = client.Do(context.Background(), cfg)
+	var schemaErr *SchemaError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Len(t, schemaErr.Errors, 2)
+	assert.EqualError(t, err, "response does not match schema: /id: expected integer, got string (and 1 more)")
+
+	// Mismatches of a schema that is not enforced are returned with the response
+	cfg.EnforceResponseSchema = false
+	resp, err := client.Do(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "1", "name": float64(2)}, resp.Data)
+	assert.Len(t, resp.SchemaErrors, 2)
+}
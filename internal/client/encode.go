@@ -0,0 +1,139 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// xmlArrayItem is the element of the items of a top-level array in XML bodies
+const xmlArrayItem = "item"
+
+// EncodeBody encodes a request body in the given encoding. XML bodies are wrapped in
+// a root element; map keys become child elements and array items repeat their element,
+// except top-level array items, which become item elements of the root. Numbers decoded
+// as json.Number are written as they are.
+func EncodeBody(data interface{}, encoding, root string) ([]byte, error) {
+	switch encoding {
+	case encodingXML:
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		encoder := xml.NewEncoder(&buf)
+		if items, ok := data.([]interface{}); ok {
+			data = map[string]interface{}{xmlArrayItem: items}
+		}
+		if err := encodeXMLElement(encoder, root, data); err != nil {
+			return nil, fmt.Errorf("failed to encode XML body: %w", err)
+		}
+		if err := encoder.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to encode XML body: %w", err)
+		}
+		return buf.Bytes(), nil
+	case encodingYAML:
+		body, err := yaml.Marshal(yamlNumbers(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode YAML body: %w", err)
+		}
+		return body, nil
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(data); err != nil {
+			return nil, fmt.Errorf("failed to encode JSON body: %w", err)
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+}
+
+// encodeXMLElement writes a value as an XML element, in sorted key order for maps
+func encodeXMLElement(encoder *xml.Encoder, name string, value interface{}) error {
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if err := encodeXMLElement(encoder, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !config.XMLNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid XML element name %q", name)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeXMLElement(encoder, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(formatScalar(v))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// formatScalar formats a scalar as text, without exponents for floats
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// yamlNumbers replaces the json.Number values of a decoded document with YAML number nodes,
+// which the YAML encoder writes as they are
+func yamlNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = yamlNumbers(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = yamlNumbers(item)
+		}
+		return converted
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	default:
+		return value
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeBody(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "Ada & Co",
+		"tags":  []interface{}{"a", "b"},
+		"owner": map[string]interface{}{"id": float64(7), "active": true},
+		"note":  nil,
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		expected string
+	}{
+		{
+			name:     "json",
+			encoding: "json",
+			expected: `{"name":"Ada & Co","note":null,"owner":{"active":true,"id":7},"tags":["a","b"]}`,
+		},
+		{
+			name:     "yaml",
+			encoding: "yaml",
+			expected: "name: Ada & Co\nnote: null\nowner:\n    active: true\n    id: 7\ntags:\n    - a\n    - b\n",
+		},
+		{
+			name:     "xml",
+			encoding: "xml",
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<user><name>Ada &amp; Co</name><note></note><owner><active>true</active><id>7</id></owner>` +
+				`<tags>a</tags><tags>b</tags></user>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := EncodeBody(data, tt.encoding, "user")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+}
+
+func TestEncodeBody_Numbers(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"qty":1000000,"id":9007199254740993,"price":12.50,"rate":1e-7}`))
+	decoder.UseNumber()
+	var data interface{}
+	require.NoError(t, decoder.Decode(&data))
+
+	tests := []struct {
+		name     string
+		encoding string
+		expected string
+	}{
+		{
+			name:     "json",
+			encoding: "json",
+			expected: `{"id":9007199254740993,"price":12.50,"qty":1000000,"rate":1e-7}`,
+		},
+		{
+			name:     "yaml",
+			encoding: "yaml",
+			expected: "id: 9007199254740993\nprice: 12.50\nqty: 1000000\nrate: 1e-7\n",
+		},
+		{
+			name:     "xml",
+			encoding: "xml",
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<order><id>9007199254740993</id><price>12.50</price><qty>1000000</qty><rate>1e-7</rate></order>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := EncodeBody(data, tt.encoding, "order")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+
+	// Configured constants are floats, written without exponents
+	body, err := EncodeBody(map[string]interface{}{"qty": float64(1000000)}, "xml", "order")
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "<qty>1000000</qty>")
+}
+
+func TestEncodeBody_XMLArray(t *testing.T) {
+	body, err := EncodeBody([]interface{}{map[string]interface{}{"id": "a"}, "b"}, "xml", "users")
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<users><item><id>a</id></item><item>b</item></users>`, string(body))
+}
+
+func TestEncodeBody_InvalidXMLName(t *testing.T) {
+	_, err := EncodeBody(map[string]interface{}{"first name": "Ada"}, "xml", "user")
+	assert.ErrorContains(t, err, `invalid XML element name "first name"`)
+}
@@ -4,12 +4,21 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/net/http/httpguts"
 	"gopkg.in/yaml.v3"
+
+	"github.com/TrueTickets/api-aggregator/internal/schema"
 )
 
 // Config represents the entire service configuration
@@ -23,14 +32,308 @@ type Config struct {
 	LogFormat       string        `yaml:"log_format"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 
+	// TLS termination for the listener
+	TLS ServerTLS `yaml:"tls,omitempty"`
+
+	// Serve HTTP/2 over TLS (default true)
+	HTTP2 *bool `yaml:"http2,omitempty"`
+
+	// Serve cleartext HTTP/2 (h2c) for service meshes that terminate TLS
+	H2C bool `yaml:"h2c,omitempty"`
+
 	// OpenTelemetry configuration
 	TracingEnabled  bool   `yaml:"tracing_enabled"`
 	TracingEndpoint string `yaml:"tracing_endpoint"`
 	MetricsEnabled  bool   `yaml:"metrics_enabled"`
 	ServiceName     string `yaml:"service_name"`
 
+	// Default HTTP transport settings for backend connections
+	Transport Transport `yaml:"transport,omitempty"`
+
+	// HTTP transport settings per backend host (overrides the defaults field by field)
+	HostTransports map[string]Transport `yaml:"host_transports,omitempty"`
+
+	// Backend services referenced by name from backends and backend templates
+	Upstreams map[string]Upstream `yaml:"upstreams,omitempty"`
+
+	// Backend settings shared by several backends, referenced by name from backends
+	BackendTemplates map[string]Backend `yaml:"backend_templates,omitempty"`
+
+	// OpenAPI document describing the endpoints
+	OpenAPI OpenAPI `yaml:"openapi,omitempty"`
+
 	// Endpoints configuration
 	Endpoints []Endpoint `yaml:"endpoints"`
+
+	// Accept keys that match no field, e.g. from a newer version, instead of failing to load
+	AllowUnknownFields bool `yaml:"allow_unknown_fields,omitempty"`
+
+	// Further configuration files or glob patterns, relative to this file
+	Include []string `yaml:"include,omitempty"`
+
+	// YAML keys that match no field, reported by Lint
+	unknownFields []string
+
+	// Files the configuration was loaded from, when there are several
+	files []string
+
+	// File each top-level setting, host transport, upstream and backend template is set in,
+	// when there are several files
+	settingFiles map[string]string
+
+	// Interpolated scalars redacted from dumps
+	secrets []secretScalar
+
+	// Loaded without reading certificates and secrets, see LoadOptions
+	offline bool
+}
+
+// OpenAPI configures the OpenAPI document generated from the endpoints
+type OpenAPI struct {
+	// Serve the document (default false)
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Path the document is served at (default /openapi.json)
+	Path string `yaml:"path,omitempty"`
+
+	// Document title (defaults to the service name) and API version (defaults to the build version)
+	Title       string `yaml:"title,omitempty"`
+	Version     string `yaml:"version,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// Base URLs of the service
+	Servers []string `yaml:"servers,omitempty"`
+}
+
+// Transport represents HTTP transport settings for backend connections
+type Transport struct {
+	// Connection pool limits
+	MaxIdleConns        int `yaml:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host,omitempty"`
+	MaxConnsPerHost     int `yaml:"max_conns_per_host,omitempty"`
+
+	// How long idle connections are kept in the pool
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout,omitempty"`
+
+	// TCP keep-alive period and whether to reuse connections at all
+	KeepAlive         time.Duration `yaml:"keep_alive,omitempty"`
+	DisableKeepAlives *bool         `yaml:"disable_keep_alives,omitempty"`
+
+	// Enable or disable HTTP/2 for TLS connections
+	HTTP2 *bool `yaml:"http2,omitempty"`
+
+	// Connection-level timeouts
+	DialTimeout           time.Duration `yaml:"dial_timeout,omitempty"`
+	TLSHandshakeTimeout   time.Duration `yaml:"tls_handshake_timeout,omitempty"`
+	ResponseHeaderTimeout time.Duration `yaml:"response_header_timeout,omitempty"`
+
+	// Disable transparent gzip compression of backend responses
+	DisableCompression *bool `yaml:"disable_compression,omitempty"`
+
+	// TLS settings for HTTPS backends
+	TLS *TLS `yaml:"tls,omitempty"`
+}
+
+// TLS represents TLS settings for backend connections
+type TLS struct {
+	// PEM bundle of CA certificates used to verify the backend (defaults to the system pool)
+	CAFile string `yaml:"ca_file,omitempty"`
+
+	// PEM client certificate and key for mutual TLS
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+
+	// Server name used for verification and SNI (defaults to the backend host)
+	ServerName string `yaml:"server_name,omitempty"`
+
+	// Minimum TLS version (1.0, 1.1, 1.2, 1.3)
+	MinVersion string `yaml:"min_version,omitempty"`
+
+	// Skip certificate verification - for development only
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// ClientConfig loads the certificates and builds a TLS client configuration
+func (t TLS) ClientConfig() (*tls.Config, error) {
+	return t.clientConfig(false)
+}
+
+// clientConfig builds a TLS client configuration, loading the certificates unless offline
+func (t TLS) clientConfig(offline bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // Opt-in for development, warned at startup
+	}
+
+	if t.MinVersion != "" {
+		version, err := ParseTLSVersion(t.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.MinVersion = version
+	}
+	if offline {
+		return tlsConfig, nil
+	}
+
+	if t.CAFile != "" {
+		pool, err := loadCertPool(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// ServerTLS represents TLS settings for the listener
+type ServerTLS struct {
+	// PEM certificate and key served to clients; TLS is enabled when set
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+
+	// PEM bundle of CA certificates used to verify client certificates
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
+
+	// Client certificate policy (none, request, require, verify_if_given, require_and_verify)
+	ClientAuth string `yaml:"client_auth,omitempty"`
+
+	// Minimum TLS version (1.0, 1.1, 1.2, 1.3) and allowed TLS 1.2 cipher suites by name
+	MinVersion   string   `yaml:"min_version,omitempty"`
+	CipherSuites []string `yaml:"cipher_suites,omitempty"`
+}
+
+// Enabled reports whether TLS termination is configured
+func (t ServerTLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// ServerConfig loads the certificates and builds a TLS server configuration
+func (t ServerTLS) ServerConfig() (*tls.Config, error) {
+	return t.serverConfig(false)
+}
+
+// serverConfig builds a TLS server configuration, loading the certificates unless offline
+func (t ServerTLS) serverConfig(offline bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	var err error
+	if t.MinVersion != "" {
+		tlsConfig.MinVersion, err = ParseTLSVersion(t.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range t.CipherSuites {
+		id, err := parseCipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+
+	tlsConfig.ClientAuth, err = parseClientAuth(t.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	if offline {
+		return tlsConfig, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	if t.ClientCAFile != "" {
+		tlsConfig.ClientCAs, err = loadCertPool(t.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseClientAuth parses a client certificate policy
+func parseClientAuth(clientAuth string) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid client_auth %s", clientAuth)
+	}
+}
+
+// parseCipherSuite looks up a secure cipher suite by its standard name
+func parseCipherSuite(name string) (uint16, error) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported cipher suite %s", name)
+}
+
+// HTTP2Enabled reports whether the listener serves HTTP/2
+func (c *Config) HTTP2Enabled() bool {
+	return c.HTTP2 == nil || *c.HTTP2
+}
+
+// TemplatePattern matches {source} and {source.name} placeholders in header and body templates.
+// Sources are path, query, header and claims with a name, and request_id without one.
+var TemplatePattern = regexp.MustCompile(`\{([a-z_]+)(?:\.([^{}]+))?\}`)
+
+// XMLNamePattern matches XML element names usable for body encoding
+var XMLNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ParseTLSVersion parses a TLS version such as 1.2 into its crypto/tls constant
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS version %s", version)
+	}
+}
+
+// loadCertPool reads a PEM bundle of CA certificates
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", path)
+	}
+	return pool, nil
 }
 
 // Endpoint represents a single API endpoint configuration
@@ -47,15 +350,172 @@ type Endpoint struct {
 	// Default encoding for backends (json, xml, yaml)
 	Encoding string `yaml:"encoding"`
 
+	// Time to keep waiting for optional backends once all required backends are done
+	// (optional backends may run until the endpoint timeout if zero)
+	SoftTimeout time.Duration `yaml:"soft_timeout,omitempty"`
+
 	// Backend services to aggregate
 	Backends []Backend `yaml:"backends"`
+
+	// Operations applied to arrays in the merged response, in declared order
+	Collections []CollectionOperation `yaml:"collections,omitempty"`
+
+	// Conflict resolution when merging backend responses
+	Merge MergeOptions `yaml:"merge,omitempty"`
+
+	// Static headers added to every response (override exposed backend headers)
+	ResponseHeaders map[string]string `yaml:"response_headers,omitempty"`
+
+	// Documentation of the endpoint in the OpenAPI document - optional
+	Summary     string   `yaml:"summary,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+
+	// JSON Schema of the aggregated response, only used to document the endpoint - optional
+	ResponseSchema map[string]interface{} `yaml:"response_schema,omitempty"`
+
+	// JSON Schema the request body must match, inline or from a JSON or YAML file - optional
+	RequestSchema     map[string]interface{} `yaml:"request_schema,omitempty"`
+	RequestSchemaFile string                 `yaml:"request_schema_file,omitempty"`
+
+	// Constraints on path and query parameters, by parameter name - optional
+	PathParams  map[string]ParamConstraint `yaml:"path_params,omitempty"`
+	QueryParams map[string]ParamConstraint `yaml:"query_params,omitempty"`
+
+	// File the endpoint is defined in
+	source string
+}
+
+// ParamConstraint restricts the values of a path or query parameter
+type ParamConstraint struct {
+	// Value type: string, int, number, boolean or uuid (default: string)
+	Type string `yaml:"type,omitempty"`
+
+	// Regular expression the value must match
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// Allowed values
+	Enum []string `yaml:"enum,omitempty"`
+
+	// Whether a query parameter must be present; path parameters always are
+	Required bool `yaml:"required,omitempty"`
+}
+
+// LoadRequestSchema returns the endpoint's request schema document, nil if it has none
+func (e Endpoint) LoadRequestSchema() (interface{}, error) {
+	return loadSchema(e.RequestSchema, e.RequestSchemaFile)
+}
+
+// loadSchema returns an inline schema document or reads one from a JSON or YAML file
+func loadSchema(inline map[string]interface{}, file string) (interface{}, error) {
+	if file == "" {
+		if inline == nil {
+			return nil, nil
+		}
+		return inline, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file %s: %w", file, err)
+	}
+	return document, nil
+}
+
+// MergePolicy controls how conflicting values from different backends are combined
+type MergePolicy struct {
+	// Scalar conflict strategy (last_wins, first_wins, prefer_backend, error)
+	Strategy string `yaml:"strategy,omitempty"`
+
+	// Backend name whose values win when Strategy is prefer_backend
+	PreferBackend string `yaml:"prefer_backend,omitempty"`
+
+	// Array merge mode (append, replace, union)
+	Arrays string `yaml:"arrays,omitempty"`
+
+	// Field identifying array items for the union mode (whole items are compared if empty)
+	ArrayKey string `yaml:"array_key,omitempty"`
+}
+
+// MergeOptions holds the endpoint merge policy and per-path overrides
+type MergeOptions struct {
+	MergePolicy `yaml:",inline"`
+
+	// Record merge conflicts as trace events
+	RecordConflicts bool `yaml:"record_conflicts,omitempty"`
+
+	// Overrides for specific paths in the merged response (most specific path wins)
+	Paths []PathMergePolicy `yaml:"paths,omitempty"`
+}
+
+// PathMergePolicy overrides the merge policy for a path and everything below it.
+// Empty fields inherit the endpoint policy.
+type PathMergePolicy struct {
+	// Path in the merged response using dot notation
+	Path string `yaml:"path"`
+
+	MergePolicy `yaml:",inline"`
+}
+
+// CollectionOperation represents a single post-merge operation on an array in the response.
+// Exactly one of Sort, Dedupe, Filter or Limit/Offset must be set.
+type CollectionOperation struct {
+	// Path to the array using dot notation (empty for a top-level array response)
+	Path string `yaml:"path"`
+
+	// Sort items by one or more fields
+	Sort []SortField `yaml:"sort,omitempty"`
+
+	// Remove items with a duplicate value for this field, keeping the first occurrence
+	Dedupe string `yaml:"dedupe,omitempty"`
+
+	// Keep only items matching the predicate
+	Filter *FilterPredicate `yaml:"filter,omitempty"`
+
+	// Skip the first Offset items and keep at most Limit items (0 means no limit)
+	Offset int `yaml:"offset,omitempty"`
+	Limit  int `yaml:"limit,omitempty"`
+}
+
+// SortField represents a single sort key
+type SortField struct {
+	// Field to sort by using dot notation
+	Field string `yaml:"field"`
+
+	// Sort order (asc, desc)
+	Order string `yaml:"order,omitempty"`
+}
+
+// FilterPredicate represents a condition evaluated against each array item
+type FilterPredicate struct {
+	// Field to evaluate using dot notation
+	Field string `yaml:"field"`
+
+	// Comparison operator (eq, ne, gt, gte, lt, lte, in, nin, contains, exists)
+	Operator string `yaml:"operator"`
+
+	// Value to compare against (a list for in/nin, a boolean for exists)
+	Value interface{} `yaml:"value,omitempty"`
 }
 
 // Backend represents a backend service configuration
 type Backend struct {
+	// Backend template or upstream providing the fields left unset - optional
+	Use string `yaml:"use,omitempty"`
+
+	// Name identifying this backend in merge policies, logs and traces - optional
+	Name string `yaml:"name,omitempty"`
+
 	// URL pattern to call (can include path parameters) - optional, defaults to endpoint path
 	URLPattern string `yaml:"url_pattern,omitempty"`
 
+	// HTTP method for this backend - optional, defaults to the endpoint method
+	Method string `yaml:"method,omitempty"`
+
 	// Encoding for this specific backend (overrides endpoint encoding)
 	Encoding string `yaml:"encoding"`
 
@@ -65,6 +525,38 @@ type Backend struct {
 	// Headers to remove before making the request to this backend
 	RemoveHeaders []string `yaml:"remove_headers,omitempty"`
 
+	// Forward only these request headers (all headers are forwarded if empty)
+	ForwardHeaders []string `yaml:"forward_headers,omitempty"`
+
+	// Forwarded headers to rename (old name: new name)
+	RenameHeaders map[string]string `yaml:"rename_headers,omitempty"`
+
+	// Headers to set, replacing forwarded values; values are header templates
+	SetHeaders map[string]string `yaml:"set_headers,omitempty"`
+
+	// Headers to add alongside forwarded values; values are header templates
+	AddHeaders map[string]string `yaml:"add_headers,omitempty"`
+
+	// Response headers from this backend passed to the client
+	ExposeHeaders []ExposeHeader `yaml:"expose_headers,omitempty"`
+
+	// Request body shaping for this backend (the ingress body is forwarded as is if not set)
+	Body *RequestBody `yaml:"body,omitempty"`
+
+	// Timeout for each request to this backend (bounded by the endpoint timeout)
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Connection-level timeouts for this backend
+	ConnectTimeout        time.Duration `yaml:"connect_timeout,omitempty"`
+	TLSHandshakeTimeout   time.Duration `yaml:"tls_handshake_timeout,omitempty"`
+	ResponseHeaderTimeout time.Duration `yaml:"response_header_timeout,omitempty"`
+
+	// Optional backends are cut off at the endpoint soft timeout once all required backends are done
+	Optional bool `yaml:"optional,omitempty"`
+
+	// TLS settings for this backend (overrides the host transport TLS settings)
+	TLS *TLS `yaml:"tls,omitempty"`
+
 	// Response transformations
 	Group   string            `yaml:"group,omitempty"`
 	Target  string            `yaml:"target,omitempty"`
@@ -72,28 +564,241 @@ type Backend struct {
 	Deny    []string          `yaml:"deny,omitempty"`
 	Mapping map[string]string `yaml:"mapping,omitempty"`
 	Concat  string            `yaml:"concat,omitempty"`
+
+	// Join this backend's items element-wise into an array from other backends
+	Join *Join `yaml:"join,omitempty"`
+
+	// Backends tried in order when this backend fails, using this backend's transformations
+	Fallback []Fallback `yaml:"fallback,omitempty"`
+
+	// Duplicate slow requests to reduce tail latency (idempotent methods only)
+	Hedge *Hedge `yaml:"hedge,omitempty"`
+
+	// Credentials added to requests to this backend
+	Auth *Auth `yaml:"auth,omitempty"`
+
+	// JSON Schema the decoded response must match, inline or from a JSON or YAML file - optional
+	ResponseSchema     map[string]interface{} `yaml:"response_schema,omitempty"`
+	ResponseSchemaFile string                 `yaml:"response_schema_file,omitempty"`
+
+	// What a response not matching the schema does: enforce (backend failure), warn or off
+	ResponseSchemaMode string `yaml:"response_schema_mode,omitempty"`
+
+	// YAML keys set in the configuration file, nil for backends built in code
+	setFields map[string]bool
+}
+
+// LoadResponseSchema returns the backend's response schema document, nil if it has none
+func (b Backend) LoadResponseSchema() (interface{}, error) {
+	return loadSchema(b.ResponseSchema, b.ResponseSchemaFile)
+}
+
+// RequestBody shapes the JSON ingress body into the request body for a backend.
+// Target, allow, deny and mapping work as for responses; the result is encoded in the backend encoding.
+type RequestBody struct {
+	Target  string            `yaml:"target,omitempty"`
+	Allow   []string          `yaml:"allow,omitempty"`
+	Deny    []string          `yaml:"deny,omitempty"`
+	Mapping map[string]string `yaml:"mapping,omitempty"`
+
+	// Fields to set by dot-notation path; string values are templates, other values constants
+	Set map[string]interface{} `yaml:"set,omitempty"`
+
+	// Root element name for XML bodies
+	Root string `yaml:"root,omitempty"`
+}
+
+// ExposeHeader passes a backend response header to the client
+type ExposeHeader struct {
+	// Header name; a trailing * matches by prefix (e.g. X-RateLimit-*)
+	Name string `yaml:"name"`
+
+	// Name of the header in the response (not allowed with a prefix match)
+	As string `yaml:"as,omitempty"`
+
+	// How values from several backends are combined (first, last, append, min, max, cache_control).
+	// Defaults to cache_control for Cache-Control, append for Set-Cookie and first otherwise.
+	Merge string `yaml:"merge,omitempty"`
+}
+
+// IsControlledResponseHeader reports whether a response header is set by the service and
+// cannot be exposed from backends or configured statically
+func IsControlledResponseHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Content-Type", "Content-Length", "Content-Encoding", "Transfer-Encoding",
+		"Connection", "Keep-Alive", "Upgrade", "Trailer", "Vary":
+		return true
+	}
+	return strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Api-Aggregation-")
+}
+
+// Auth represents outbound authentication for a backend
+type Auth struct {
+	// Authentication type (bearer, api_key, basic, oauth2, hmac)
+	Type string `yaml:"type"`
+
+	// Token for bearer and api_key
+	Token Secret `yaml:"token,omitempty"`
+
+	// Header carrying the api_key or hmac signature (defaults to X-API-Key and X-Signature)
+	Header string `yaml:"header,omitempty"`
+
+	// Credentials for basic
+	Username string `yaml:"username,omitempty"`
+	Password Secret `yaml:"password,omitempty"`
+
+	// Client credentials grant for oauth2
+	TokenURL     string   `yaml:"token_url,omitempty"`
+	ClientID     string   `yaml:"client_id,omitempty"`
+	ClientSecret Secret   `yaml:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	Audience     string   `yaml:"audience,omitempty"`
+
+	// Signing key for hmac
+	KeyID     string `yaml:"key_id,omitempty"`
+	Secret    Secret `yaml:"secret,omitempty"`
+	Algorithm string `yaml:"algorithm,omitempty"`
+}
+
+// Secret is a credential read from the configuration, an environment variable or a file
+type Secret struct {
+	Value string `yaml:"value,omitempty"`
+	Env   string `yaml:"env,omitempty"`
+	File  string `yaml:"file,omitempty"`
+}
+
+// redactedSecret replaces inline secret values in configuration dumps
+const redactedSecret = "REDACTED"
+
+// MarshalYAML redacts inline secret values so configuration dumps never reveal them
+func (s Secret) MarshalYAML() (interface{}, error) {
+	type plain Secret
+	if s.Value != "" {
+		s.Value = redactedSecret
+	}
+	return plain(s), nil
+}
+
+// IsSet reports whether a source is configured for the secret
+func (s Secret) IsSet() bool {
+	return s.Value != "" || s.Env != "" || s.File != ""
+}
+
+// Resolve returns the secret value. Trailing newlines are trimmed from files.
+func (s Secret) Resolve() (string, error) {
+	switch {
+	case s.Env != "":
+		value, ok := os.LookupEnv(s.Env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	case s.File != "":
+		data, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return s.Value, nil
+	}
+}
+
+// Hedge configures a duplicate request issued when a backend is slow to respond
+type Hedge struct {
+	// Delay before issuing the hedged request (initial delay when adaptive)
+	Delay time.Duration `yaml:"delay,omitempty"`
+
+	// Derive the delay from the observed p95 latency once enough samples are collected
+	Adaptive bool `yaml:"adaptive,omitempty"`
+
+	// Hosts to send hedged requests to, in rotation (defaults to the backend host)
+	Hosts []string `yaml:"hosts,omitempty"`
+
+	// Maximum hedged requests per second for this backend
+	MaxPerSecond int `yaml:"max_per_second,omitempty"`
+}
+
+// Fallback represents an alternative location for a backend
+type Fallback struct {
+	// Host for this fallback
+	Host string `yaml:"host"`
+
+	// URL pattern to call - optional, defaults to the primary backend URL pattern
+	URLPattern string `yaml:"url_pattern,omitempty"`
+
+	// Encoding for this fallback - optional, defaults to the primary backend encoding
+	Encoding string `yaml:"encoding,omitempty"`
+}
+
+// Label returns the backend name, falling back to its host and URL pattern
+func (b Backend) Label() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return b.Host + b.URLPattern
+}
+
+// Join represents a key-based join of a backend response into an existing array
+type Join struct {
+	// Path to the target array in the merged response using dot notation
+	Path string `yaml:"path"`
+
+	// Key field on the target array items
+	Key string `yaml:"key"`
+
+	// Key field on this backend's items (defaults to Key)
+	ForeignKey string `yaml:"foreign_key,omitempty"`
+
+	// Join type (left, inner)
+	Type string `yaml:"type,omitempty"`
+}
+
+// LoadOptions controls how a configuration is loaded
+type LoadOptions struct {
+	// Apply the API_AGGREGATOR_* environment variable overrides
+	Env bool
+
+	// Check the configuration without reading certificates, key files and secrets, e.g. in CI
+	// where they are not available. ${file:path} references are left unexpanded.
+	Offline bool
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file, the YAML files of a directory or the files
+// matching a glob pattern, along with the files they include
 func LoadConfig(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	return LoadConfigWithOptions(path, LoadOptions{})
+}
+
+// LoadConfigWithOptions loads configuration like LoadConfig, with the given options
+func LoadConfigWithOptions(path string, options LoadOptions) (*Config, error) {
+	loader := configLoader{seen: make(map[string]bool), offline: options.Offline}
+	if err := loader.load(path); err != nil {
+		return nil, err
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	cfg, err := loader.merge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config files: %w", err)
+	}
+	if len(cfg.unknownFields) > 0 && !cfg.AllowUnknownFields {
+		return nil, fmt.Errorf("failed to parse config file: %s", strings.Join(cfg.unknownFields, "; "))
 	}
 
 	// Set defaults
 	cfg.setDefaults()
 
 	// Validate configuration
+	cfg.offline = options.Offline
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	if options.Env {
+		if err := cfg.applyEnv(); err != nil {
+			return nil, err
+		}
+	}
 	return &cfg, nil
 }
 
@@ -104,41 +809,46 @@ func LoadConfigFromEnv() (*Config, error) {
 		configPath = "config.yaml"
 	}
 
-	cfg, err := LoadConfig(configPath)
-	if err != nil {
-		return nil, err
-	}
+	return LoadConfigWithEnv(configPath)
+}
+
+// LoadConfigWithEnv loads configuration from a YAML file and applies the environment variable overrides
+func LoadConfigWithEnv(configPath string) (*Config, error) {
+	return LoadConfigWithOptions(configPath, LoadOptions{Env: true})
+}
 
-	// Override with environment variables if present
+// applyEnv overrides settings with the environment variables that are present
+func (c *Config) applyEnv() error {
+	var err error
 	if port := os.Getenv("API_AGGREGATOR_PORT"); port != "" {
-		cfg.Port = port
+		c.Port = port
 	}
 	if logLevel := os.Getenv("API_AGGREGATOR_LOG_LEVEL"); logLevel != "" {
-		cfg.LogLevel = logLevel
+		c.LogLevel = logLevel
 	}
 	if logFormat := os.Getenv("API_AGGREGATOR_LOG_FORMAT"); logFormat != "" {
-		cfg.LogFormat = logFormat
+		c.LogFormat = logFormat
 	}
 	if tracingEnabled := os.Getenv("API_AGGREGATOR_TRACING_ENABLED"); tracingEnabled != "" {
-		cfg.TracingEnabled, err = strconv.ParseBool(tracingEnabled)
+		c.TracingEnabled, err = strconv.ParseBool(tracingEnabled)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse API_AGGREGATOR_TRACING_ENABLED as boolean: %w", err)
+			return fmt.Errorf("failed to parse API_AGGREGATOR_TRACING_ENABLED as boolean: %w", err)
 		}
 	}
 	if tracingEndpoint := os.Getenv("API_AGGREGATOR_TRACING_ENDPOINT"); tracingEndpoint != "" {
-		cfg.TracingEndpoint = tracingEndpoint
+		c.TracingEndpoint = tracingEndpoint
 	}
 	if metricsEnabled := os.Getenv("API_AGGREGATOR_METRICS_ENABLED"); metricsEnabled != "" {
-		cfg.MetricsEnabled, err = strconv.ParseBool(metricsEnabled)
+		c.MetricsEnabled, err = strconv.ParseBool(metricsEnabled)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse API_AGGREGATOR_METRICS_ENABLED as boolean: %w", err)
+			return fmt.Errorf("failed to parse API_AGGREGATOR_METRICS_ENABLED as boolean: %w", err)
 		}
 	}
 	if serviceName := os.Getenv("API_AGGREGATOR_SERVICE_NAME"); serviceName != "" {
-		cfg.ServiceName = serviceName
+		c.ServiceName = serviceName
 	}
 
-	return cfg, nil
+	return nil
 }
 
 const (
@@ -147,15 +857,40 @@ const (
 	defaultLogFormat       = "json"
 	defaultShutdownTimeout = 15 * time.Second
 	defaultServiceName     = "api-aggregator"
+	defaultOpenAPIPath     = "/openapi.json"
 	defaultTimeout         = 10 * time.Second
 	defaultMethod          = "GET"
 	defaultEncoding        = "json"
+	defaultSortOrder       = "asc"
+	defaultJoinType        = "left"
+	defaultMergeStrategy   = "last_wins"
+	defaultArrayMerge      = "append"
+	defaultHedgeDelay      = 100 * time.Millisecond
+	defaultHedgeRate       = 10
+	defaultClientAuth      = "require_and_verify"
+	defaultAPIKeyHeader    = "X-API-Key"
+	defaultSignatureHeader = "X-Signature"
+	defaultHMACAlgorithm   = "sha256"
+	defaultExposeMerge     = "first"
+	defaultXMLRoot         = "request"
+	defaultParamType       = "string"
+
+	defaultResponseSchemaMode = "enforce"
+
+	defaultMaxIdleConns        = 256
+	defaultMaxIdleConnsPerHost = 64
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
 )
 
 // setDefaults sets default values for configuration
 func (c *Config) setDefaults() {
 	c.setServiceDefaults()
+	c.setServerTLSDefaults()
 	c.setTimeoutDefaults()
+	c.setTransportDefaults()
 	c.setEndpointDefaults()
 }
 
@@ -175,21 +910,145 @@ func (c *Config) setServiceDefaults() {
 	if c.ServiceName == "" {
 		c.ServiceName = defaultServiceName
 	}
+	if c.OpenAPI.Path == "" {
+		c.OpenAPI.Path = defaultOpenAPIPath
+	}
 }
 
-func (c *Config) setTimeoutDefaults() {
+func (c *Config) setServerTLSDefaults() {
+	// Verifying client certificates is the point of configuring a client CA
+	if c.TLS.ClientAuth == "" && c.TLS.ClientCAFile != "" {
+		c.TLS.ClientAuth = defaultClientAuth
+	}
+}
+
+func (c *Config) setTimeoutDefaults() {
 	if c.Timeout == 0 {
 		c.Timeout = defaultTimeout
 	}
 }
 
+func (c *Config) setTransportDefaults() {
+	t := &c.Transport
+	if t.MaxIdleConns == 0 {
+		t.MaxIdleConns = defaultMaxIdleConns
+	}
+	if t.MaxIdleConnsPerHost == 0 {
+		t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if t.IdleConnTimeout == 0 {
+		t.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if t.KeepAlive == 0 {
+		t.KeepAlive = defaultKeepAlive
+	}
+	if t.DialTimeout == 0 {
+		t.DialTimeout = defaultDialTimeout
+	}
+	if t.TLSHandshakeTimeout == 0 {
+		t.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+}
+
+// TransportFor returns the effective transport settings for a backend host: the defaults,
+// overridden by the host settings, overridden by the backend connection timeouts
+func (c *Config) TransportFor(backend Backend) Transport {
+	effective := c.Transport
+	if override, ok := c.hostTransport(backend.Host); ok {
+		effective = effective.merge(override)
+	}
+	return effective.merge(Transport{
+		DialTimeout:           backend.ConnectTimeout,
+		TLSHandshakeTimeout:   backend.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: backend.ResponseHeaderTimeout,
+		TLS:                   backend.TLS,
+	})
+}
+
+// hostTransport returns the transport settings configured for a host, matching either the
+// full host URL or its host:port part
+func (c *Config) hostTransport(host string) (Transport, bool) {
+	if t, ok := c.hostTransportSetting(host); ok {
+		return t, true
+	}
+	return c.upstreamTransport(host)
+}
+
+// hostTransportSetting returns the host_transports entry for a host
+func (c *Config) hostTransportSetting(host string) (Transport, bool) {
+	if t, ok := c.HostTransports[strings.TrimSuffix(host, "/")]; ok {
+		return t, true
+	}
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		if t, ok := c.HostTransports[u.Host]; ok {
+			return t, true
+		}
+	}
+	return Transport{}, false
+}
+
+// merge returns the settings with every non-zero field of override applied
+func (t Transport) merge(override Transport) Transport {
+	if override.MaxIdleConns != 0 {
+		t.MaxIdleConns = override.MaxIdleConns
+	}
+	if override.MaxIdleConnsPerHost != 0 {
+		t.MaxIdleConnsPerHost = override.MaxIdleConnsPerHost
+	}
+	if override.MaxConnsPerHost != 0 {
+		t.MaxConnsPerHost = override.MaxConnsPerHost
+	}
+	if override.IdleConnTimeout != 0 {
+		t.IdleConnTimeout = override.IdleConnTimeout
+	}
+	if override.KeepAlive != 0 {
+		t.KeepAlive = override.KeepAlive
+	}
+	if override.DisableKeepAlives != nil {
+		t.DisableKeepAlives = override.DisableKeepAlives
+	}
+	if override.HTTP2 != nil {
+		t.HTTP2 = override.HTTP2
+	}
+	if override.DialTimeout != 0 {
+		t.DialTimeout = override.DialTimeout
+	}
+	if override.TLSHandshakeTimeout != 0 {
+		t.TLSHandshakeTimeout = override.TLSHandshakeTimeout
+	}
+	if override.ResponseHeaderTimeout != 0 {
+		t.ResponseHeaderTimeout = override.ResponseHeaderTimeout
+	}
+	if override.DisableCompression != nil {
+		t.DisableCompression = override.DisableCompression
+	}
+	if override.TLS != nil {
+		t.TLS = override.TLS
+	}
+	return t
+}
+
 func (c *Config) setEndpointDefaults() {
 	for i := range c.Endpoints {
 		endpoint := &c.Endpoints[i]
 		c.setEndpointTimeout(endpoint)
 		c.setEndpointMethod(endpoint)
 		c.setEndpointEncoding(endpoint)
-		c.setBackendDefaults(endpoint)
+		c.setBackendDefaults(i, endpoint)
+		c.setCollectionDefaults(endpoint)
+		c.setMergeDefaults(endpoint)
+		c.setParamDefaults(endpoint)
+	}
+}
+
+func (c *Config) setParamDefaults(endpoint *Endpoint) {
+	for _, params := range []map[string]ParamConstraint{endpoint.PathParams, endpoint.QueryParams} {
+		for name, constraint := range params {
+			if constraint.Type == "" {
+				constraint.Type = defaultParamType
+				params[name] = constraint
+			}
+		}
 	}
 }
 
@@ -211,9 +1070,10 @@ func (c *Config) setEndpointEncoding(endpoint *Endpoint) {
 	}
 }
 
-func (c *Config) setBackendDefaults(endpoint *Endpoint) {
+func (c *Config) setBackendDefaults(i int, endpoint *Endpoint) {
 	for j := range endpoint.Backends {
 		backend := &endpoint.Backends[j]
+		c.applyBackendTemplate(backend, yamlPath{endpointsKey, strconv.Itoa(i), "backends", strconv.Itoa(j)})
 		if backend.Encoding == "" {
 			backend.Encoding = endpoint.Encoding
 		}
@@ -221,6 +1081,97 @@ func (c *Config) setBackendDefaults(endpoint *Endpoint) {
 		if backend.URLPattern == "" {
 			backend.URLPattern = endpoint.Endpoint
 		}
+		if backend.Method == "" {
+			backend.Method = endpoint.Method
+		}
+		backend.Method = strings.ToUpper(backend.Method)
+		for k := range backend.Fallback {
+			fallback := &backend.Fallback[k]
+			if fallback.URLPattern == "" {
+				fallback.URLPattern = backend.URLPattern
+			}
+			if fallback.Encoding == "" {
+				fallback.Encoding = backend.Encoding
+			}
+		}
+		if backend.Hedge != nil {
+			if backend.Hedge.Delay == 0 {
+				backend.Hedge.Delay = defaultHedgeDelay
+			}
+			if backend.Hedge.MaxPerSecond == 0 {
+				backend.Hedge.MaxPerSecond = defaultHedgeRate
+			}
+		}
+		if backend.Auth != nil {
+			c.setAuthDefaults(backend.Auth)
+		}
+		for k := range backend.ExposeHeaders {
+			c.setExposeHeaderDefaults(&backend.ExposeHeaders[k])
+		}
+		if backend.Body != nil && backend.Body.Root == "" {
+			backend.Body.Root = defaultXMLRoot
+		}
+		if backend.ResponseSchemaMode == "" && (backend.ResponseSchema != nil || backend.ResponseSchemaFile != "") {
+			backend.ResponseSchemaMode = defaultResponseSchemaMode
+		}
+		if backend.Join != nil {
+			if backend.Join.ForeignKey == "" {
+				backend.Join.ForeignKey = backend.Join.Key
+			}
+			if backend.Join.Type == "" {
+				backend.Join.Type = defaultJoinType
+			}
+		}
+	}
+}
+
+func (c *Config) setExposeHeaderDefaults(expose *ExposeHeader) {
+	if expose.Merge != "" {
+		return
+	}
+	switch http.CanonicalHeaderKey(expose.Name) {
+	case "Cache-Control":
+		expose.Merge = "cache_control"
+	case "Set-Cookie":
+		expose.Merge = "append"
+	default:
+		expose.Merge = defaultExposeMerge
+	}
+}
+
+func (c *Config) setAuthDefaults(auth *Auth) {
+	switch auth.Type {
+	case "api_key":
+		if auth.Header == "" {
+			auth.Header = defaultAPIKeyHeader
+		}
+	case "hmac":
+		if auth.Header == "" {
+			auth.Header = defaultSignatureHeader
+		}
+		if auth.Algorithm == "" {
+			auth.Algorithm = defaultHMACAlgorithm
+		}
+	}
+}
+
+func (c *Config) setCollectionDefaults(endpoint *Endpoint) {
+	for j := range endpoint.Collections {
+		operation := &endpoint.Collections[j]
+		for k := range operation.Sort {
+			if operation.Sort[k].Order == "" {
+				operation.Sort[k].Order = defaultSortOrder
+			}
+		}
+	}
+}
+
+func (c *Config) setMergeDefaults(endpoint *Endpoint) {
+	if endpoint.Merge.Strategy == "" {
+		endpoint.Merge.Strategy = defaultMergeStrategy
+	}
+	if endpoint.Merge.Arrays == "" {
+		endpoint.Merge.Arrays = defaultArrayMerge
 	}
 }
 
@@ -230,14 +1181,139 @@ func (c *Config) validate() error {
 		return fmt.Errorf("no endpoints configured")
 	}
 
+	if err := c.validateH2C(); err != nil {
+		return c.inFile("h2c", err)
+	}
+
+	if err := c.validateServerTLS(); err != nil {
+		return c.inFile("tls", err)
+	}
+
+	if err := c.validateTransport("transport", c.Transport); err != nil {
+		return c.inFile("transport", err)
+	}
+	for _, host := range sortedNames(c.HostTransports) {
+		if err := c.validateTransport(fmt.Sprintf("host transport %s", host), c.HostTransports[host]); err != nil {
+			return c.inFile(hostTransportsKey+" "+host, err)
+		}
+	}
+
+	if err := c.validateTemplates(); err != nil {
+		return err
+	}
+
 	validEncodings := c.getValidEncodings()
 
 	for i, endpoint := range c.Endpoints {
 		if err := c.validateEndpoint(i, endpoint, validEncodings); err != nil {
+			// Name the file when the configuration is split across several
+			if len(c.files) > 1 {
+				return fmt.Errorf("%s: %w", endpoint.source, err)
+			}
 			return err
 		}
 	}
 
+	if err := c.validateOpenAPI(); err != nil {
+		return c.inFile("openapi", err)
+	}
+	return nil
+}
+
+// inFile names the file a setting is set in when the configuration is split across several
+func (c *Config) inFile(setting string, err error) error {
+	if file, ok := c.settingFiles[setting]; ok {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return err
+}
+
+// validateOpenAPI checks that the OpenAPI document path does not shadow another route
+func (c *Config) validateOpenAPI() error {
+	if !c.OpenAPI.Enabled {
+		return nil
+	}
+
+	path := c.OpenAPI.Path
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, "{}*") {
+		return fmt.Errorf("openapi: invalid path %s", path)
+	}
+	if path == "/livez" || path == "/readyz" {
+		return fmt.Errorf("openapi: path %s is reserved for health checks", path)
+	}
+	for _, endpoint := range c.Endpoints {
+		if endpoint.Endpoint == path && strings.EqualFold(endpoint.Method, http.MethodGet) {
+			return fmt.Errorf("openapi: path %s conflicts with endpoint %s", path, endpoint.Endpoint)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateTransport(name string, t Transport) error {
+	if t.MaxIdleConns < 0 || t.MaxIdleConnsPerHost < 0 || t.MaxConnsPerHost < 0 {
+		return fmt.Errorf("%s: connection limits must not be negative", name)
+	}
+	if t.IdleConnTimeout < 0 || t.KeepAlive < 0 || t.DialTimeout < 0 ||
+		t.TLSHandshakeTimeout < 0 || t.ResponseHeaderTimeout < 0 {
+		return fmt.Errorf("%s: timeouts must not be negative", name)
+	}
+	if t.TLS != nil {
+		return c.validateTLS(name, *t.TLS)
+	}
+	return nil
+}
+
+// validateH2C checks that cleartext HTTP/2 is combined with HTTP/2 and without TLS
+func (c *Config) validateH2C() error {
+	if !c.H2C {
+		return nil
+	}
+	if c.TLS.Enabled() {
+		return fmt.Errorf("h2c cannot be combined with tls")
+	}
+	if !c.HTTP2Enabled() {
+		return fmt.Errorf("h2c requires http2")
+	}
+	return nil
+}
+
+// validateServerTLS checks the listener TLS settings
+func (c *Config) validateServerTLS() error {
+	t := c.TLS
+	if !t.Enabled() {
+		if t.KeyFile != "" || t.ClientCAFile != "" {
+			return fmt.Errorf("tls: cert_file is required")
+		}
+		return nil
+	}
+	if t.KeyFile == "" {
+		return fmt.Errorf("tls: key_file is required")
+	}
+
+	if _, err := parseClientAuth(t.ClientAuth); err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	switch t.ClientAuth {
+	case "verify_if_given", "require_and_verify":
+		if t.ClientCAFile == "" {
+			return fmt.Errorf("tls: client_auth %s requires client_ca_file", t.ClientAuth)
+		}
+	}
+
+	if _, err := t.serverConfig(c.offline); err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	return nil
+}
+
+// validateTLS checks that TLS certificates can be loaded
+func (c *Config) validateTLS(name string, t TLS) error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("%s: tls cert_file and key_file must be set together", name)
+	}
+	if _, err := t.clientConfig(c.offline); err != nil {
+		return fmt.Errorf("%s: invalid tls configuration: %w", name, err)
+	}
 	return nil
 }
 
@@ -258,7 +1334,101 @@ func (c *Config) validateEndpoint(i int, endpoint Endpoint, validEncodings map[s
 		return fmt.Errorf("endpoint %s: invalid encoding %s", endpoint.Endpoint, endpoint.Encoding)
 	}
 
-	return c.validateBackends(endpoint, validEncodings)
+	if endpoint.SoftTimeout < 0 {
+		return fmt.Errorf("endpoint %s: soft_timeout must not be negative", endpoint.Endpoint)
+	}
+
+	if err := c.validateBackends(endpoint, validEncodings); err != nil {
+		return err
+	}
+
+	if err := c.validateCollections(endpoint); err != nil {
+		return err
+	}
+
+	for name := range endpoint.ResponseHeaders {
+		if !httpguts.ValidHeaderFieldName(name) || IsControlledResponseHeader(name) {
+			return fmt.Errorf("endpoint %s: response header %s cannot be set", endpoint.Endpoint, name)
+		}
+	}
+
+	if err := c.validateRequestValidation(endpoint); err != nil {
+		return fmt.Errorf("endpoint %s: %w", endpoint.Endpoint, err)
+	}
+
+	return c.validateMerge(endpoint)
+}
+
+// validateRequestValidation checks the request schema and parameter constraints of an endpoint
+func (c *Config) validateRequestValidation(endpoint Endpoint) error {
+	if endpoint.RequestSchema != nil || endpoint.RequestSchemaFile != "" {
+		if endpoint.RequestSchema != nil && endpoint.RequestSchemaFile != "" {
+			return fmt.Errorf("request_schema and request_schema_file are mutually exclusive")
+		}
+		if !AllowsRequestBody(endpoint.Method) {
+			return fmt.Errorf("request_schema requires a POST, PUT or PATCH method, got %s", endpoint.Method)
+		}
+		document, err := endpoint.LoadRequestSchema()
+		if err != nil {
+			return fmt.Errorf("request_schema: %w", err)
+		}
+		if _, err := schema.Compile(document); err != nil {
+			return fmt.Errorf("request_schema: %w", err)
+		}
+	}
+
+	for name, constraint := range endpoint.PathParams {
+		if !hasPathParam(endpoint.Endpoint, name) {
+			return fmt.Errorf("path_params: unknown path parameter %s", name)
+		}
+		if err := validateParamConstraint(constraint); err != nil {
+			return fmt.Errorf("path_params %s: %w", name, err)
+		}
+	}
+	for name, constraint := range endpoint.QueryParams {
+		if err := validateParamConstraint(constraint); err != nil {
+			return fmt.Errorf("query_params %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateResponseSchema checks the response schema and mode of a backend
+func (c *Config) validateResponseSchema(backend Backend) error {
+	if backend.ResponseSchema != nil && backend.ResponseSchemaFile != "" {
+		return fmt.Errorf("response_schema and response_schema_file are mutually exclusive")
+	}
+
+	switch backend.ResponseSchemaMode {
+	case "", "enforce", "warn", "off":
+	default:
+		return fmt.Errorf("invalid response_schema_mode %s", backend.ResponseSchemaMode)
+	}
+
+	document, err := backend.LoadResponseSchema()
+	if err != nil {
+		return fmt.Errorf("response_schema: %w", err)
+	}
+	if document == nil {
+		return nil
+	}
+	if _, err := schema.Compile(document); err != nil {
+		return fmt.Errorf("response_schema: %w", err)
+	}
+	return nil
+}
+
+// validateParamConstraint checks the type and pattern of a parameter constraint
+func validateParamConstraint(constraint ParamConstraint) error {
+	switch constraint.Type {
+	case "string", "int", "number", "boolean", "uuid":
+	default:
+		return fmt.Errorf("invalid type %s", constraint.Type)
+	}
+	if _, err := regexp.Compile(constraint.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	return nil
 }
 
 func (c *Config) validateBackends(endpoint Endpoint, validEncodings map[string]bool) error {
@@ -266,21 +1436,479 @@ func (c *Config) validateBackends(endpoint Endpoint, validEncodings map[string]b
 		if err := c.validateBackend(endpoint.Endpoint, j, backend, validEncodings); err != nil {
 			return err
 		}
+		if err := c.validateHedge(endpoint, j, backend); err != nil {
+			return err
+		}
+		if backend.Auth != nil {
+			if err := c.validateAuth(*backend.Auth); err != nil {
+				return fmt.Errorf("endpoint %s, backend %d: auth: %w", endpoint.Endpoint, j, err)
+			}
+		}
+		if backend.Body != nil && !AllowsRequestBody(backend.Method) {
+			return fmt.Errorf("endpoint %s, backend %d: body requires a POST, PUT or PATCH method, got %s",
+				endpoint.Endpoint, j, backend.Method)
+		}
+		if len(endpoint.Backends) > 1 {
+			if err := validateExposedValidators(backend); err != nil {
+				return fmt.Errorf("endpoint %s, backend %d: %w", endpoint.Endpoint, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateExposedValidators rejects exposing a backend's cache validators under their own name
+// when there are several backends: clients would revalidate the merged response with them, and
+// the conditional headers forwarded to every backend would make the others answer 304
+func validateExposedValidators(backend Backend) error {
+	for _, expose := range backend.ExposeHeaders {
+		switch http.CanonicalHeaderKey(expose.Name) {
+		case "Etag", "Last-Modified":
+			if expose.As == "" {
+				return fmt.Errorf("expose header %s: must be renamed with as when the endpoint has several backends",
+					expose.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateAuth(auth Auth) error {
+	var secrets []Secret
+	switch auth.Type {
+	case "bearer", "api_key":
+		if !auth.Token.IsSet() {
+			return fmt.Errorf("token is required for %s", auth.Type)
+		}
+		secrets = append(secrets, auth.Token)
+	case "basic":
+		if auth.Username == "" {
+			return fmt.Errorf("username is required for basic")
+		}
+		secrets = append(secrets, auth.Password)
+	case "oauth2":
+		if auth.TokenURL == "" || auth.ClientID == "" || !auth.ClientSecret.IsSet() {
+			return fmt.Errorf("token_url, client_id and client_secret are required for oauth2")
+		}
+		if _, err := url.ParseRequestURI(auth.TokenURL); err != nil {
+			return fmt.Errorf("invalid token_url %s", auth.TokenURL)
+		}
+		secrets = append(secrets, auth.ClientSecret)
+	case "hmac":
+		if !auth.Secret.IsSet() {
+			return fmt.Errorf("secret is required for hmac")
+		}
+		if auth.Algorithm != "sha256" && auth.Algorithm != "sha512" {
+			return fmt.Errorf("invalid hmac algorithm %s", auth.Algorithm)
+		}
+		secrets = append(secrets, auth.Secret)
+	default:
+		return fmt.Errorf("invalid type %s", auth.Type)
+	}
+
+	// Fail at load time rather than on the first request
+	if c.offline {
+		return nil
+	}
+	for _, secret := range secrets {
+		if _, err := secret.Resolve(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateHedge(endpoint Endpoint, j int, backend Backend) error {
+	if backend.Hedge == nil {
+		return nil
+	}
+
+	if !IsIdempotentMethod(backend.Method) {
+		return fmt.Errorf("endpoint %s, backend %d: hedging requires an idempotent method, got %s",
+			endpoint.Endpoint, j, backend.Method)
+	}
+
+	if backend.Hedge.Delay < 0 {
+		return fmt.Errorf("endpoint %s, backend %d: hedge delay must not be negative", endpoint.Endpoint, j)
+	}
+
+	if backend.Hedge.MaxPerSecond < 0 {
+		return fmt.Errorf("endpoint %s, backend %d: hedge max_per_second must not be negative", endpoint.Endpoint, j)
 	}
+
 	return nil
 }
 
+// IsIdempotentMethod reports whether repeating a request with this HTTP method is safe
+func IsIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidMethod reports whether a backend can be requested with this HTTP method
+func isValidMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+		return true
+	default:
+		return false
+	}
+}
+
+// AllowsRequestBody reports whether requests with this HTTP method carry a body to backends
+func AllowsRequestBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "POST", "PUT", "PATCH":
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Config) validateBackend(endpointName string, j int, backend Backend, validEncodings map[string]bool) error {
 	// Note: URLPattern is now optional and defaults are set in setBackendDefaults
 
+	if err := c.validateBackendUse(endpointName, j, backend); err != nil {
+		return err
+	}
+
 	if backend.Host == "" {
 		return fmt.Errorf("endpoint %s, backend %d: host is required", endpointName, j)
 	}
 
+	if !isValidMethod(backend.Method) {
+		return fmt.Errorf("endpoint %s, backend %d: invalid method %s", endpointName, j, backend.Method)
+	}
+
 	if !validEncodings[backend.Encoding] {
 		return fmt.Errorf("endpoint %s, backend %d: invalid encoding %s",
 			endpointName, j, backend.Encoding)
 	}
 
+	if backend.TLS != nil {
+		if err := c.validateTLS(fmt.Sprintf("endpoint %s, backend %d", endpointName, j), *backend.TLS); err != nil {
+			return err
+		}
+	}
+
+	if backend.Timeout < 0 || backend.ConnectTimeout < 0 ||
+		backend.TLSHandshakeTimeout < 0 || backend.ResponseHeaderTimeout < 0 {
+		return fmt.Errorf("endpoint %s, backend %d: timeouts must not be negative", endpointName, j)
+	}
+
+	for k, fallback := range backend.Fallback {
+		if fallback.Host == "" {
+			return fmt.Errorf("endpoint %s, backend %d, fallback %d: host is required", endpointName, j, k)
+		}
+		if !validEncodings[fallback.Encoding] {
+			return fmt.Errorf("endpoint %s, backend %d, fallback %d: invalid encoding %s",
+				endpointName, j, k, fallback.Encoding)
+		}
+	}
+
+	// Without its own timeout the primary backend can use the whole endpoint
+	// deadline, leaving no time for the fallbacks to run
+	if len(backend.Fallback) > 0 && backend.Timeout == 0 {
+		return fmt.Errorf("endpoint %s, backend %d: fallback requires a backend timeout", endpointName, j)
+	}
+
+	if !isValidPath(backend.Group) || !isValidPath(backend.Concat) {
+		return fmt.Errorf("endpoint %s, backend %d: group and concat paths must not contain empty segments",
+			endpointName, j)
+	}
+
+	if err := c.validateHeaders(endpointName, backend); err != nil {
+		return fmt.Errorf("endpoint %s, backend %d: %w", endpointName, j, err)
+	}
+
+	if backend.Body != nil {
+		if err := c.validateRequestBody(endpointName, *backend.Body); err != nil {
+			return fmt.Errorf("endpoint %s, backend %d: body: %w", endpointName, j, err)
+		}
+	}
+
+	if err := c.validateResponseSchema(backend); err != nil {
+		return fmt.Errorf("endpoint %s, backend %d: %w", endpointName, j, err)
+	}
+
+	if backend.Join != nil {
+		return c.validateJoin(endpointName, j, backend)
+	}
+
+	return nil
+}
+
+// validateHeaders checks header names and templates of the header rules
+func (c *Config) validateHeaders(endpointPath string, backend Backend) error {
+	names := append(append([]string{}, backend.ForwardHeaders...), backend.RemoveHeaders...)
+	for from, to := range backend.RenameHeaders {
+		names = append(names, from, to)
+	}
+	for _, name := range names {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+
+	for _, rules := range []map[string]string{backend.SetHeaders, backend.AddHeaders} {
+		for name, template := range rules {
+			if !httpguts.ValidHeaderFieldName(name) {
+				return fmt.Errorf("invalid header name %q", name)
+			}
+			if err := validateTemplate(endpointPath, template); err != nil {
+				return fmt.Errorf("header %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, expose := range backend.ExposeHeaders {
+		if err := validateExposeHeader(expose); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRequestBody checks the paths and templates of a request body
+func (c *Config) validateRequestBody(endpointPath string, body RequestBody) error {
+	paths := append([]string{body.Target}, body.Allow...)
+	paths = append(paths, body.Deny...)
+	for from, to := range body.Mapping {
+		paths = append(paths, from, to)
+	}
+	for path := range body.Set {
+		if path == "" {
+			return fmt.Errorf("set path must not be empty")
+		}
+		paths = append(paths, path)
+	}
+	for _, path := range paths {
+		if !isValidPath(path) {
+			return fmt.Errorf("path %q must not contain empty segments", path)
+		}
+	}
+
+	for path, value := range body.Set {
+		if template, ok := value.(string); ok {
+			if err := validateTemplate(endpointPath, template); err != nil {
+				return fmt.Errorf("set %s: %w", path, err)
+			}
+		}
+	}
+
+	if !XMLNamePattern.MatchString(body.Root) {
+		return fmt.Errorf("invalid root element name %s", body.Root)
+	}
 	return nil
 }
+
+// validateExposeHeader checks an exposed response header rule
+func validateExposeHeader(expose ExposeHeader) error {
+	name, prefix := strings.CutSuffix(expose.Name, "*")
+	if (!prefix || name != "") && !httpguts.ValidHeaderFieldName(name) {
+		return fmt.Errorf("expose header: invalid header name %q", expose.Name)
+	}
+	if prefix && expose.As != "" {
+		return fmt.Errorf("expose header %s: as cannot be used with a prefix match", expose.Name)
+	}
+	if expose.As != "" && !httpguts.ValidHeaderFieldName(expose.As) {
+		return fmt.Errorf("expose header %s: invalid header name %q", expose.Name, expose.As)
+	}
+
+	exposed := expose.As
+	if exposed == "" {
+		exposed = expose.Name
+	}
+	if !prefix && IsControlledResponseHeader(exposed) {
+		return fmt.Errorf("expose header %s: header is set by the service", exposed)
+	}
+
+	switch expose.Merge {
+	case "first", "last", "append", "min", "max", "cache_control":
+		return nil
+	default:
+		return fmt.Errorf("expose header %s: invalid merge %s", expose.Name, expose.Merge)
+	}
+}
+
+// validateTemplate checks the placeholders of a header or body template
+func validateTemplate(endpointPath, template string) error {
+	for _, match := range TemplatePattern.FindAllStringSubmatch(template, -1) {
+		source, name := match[1], match[2]
+		switch source {
+		case "request_id":
+			if name != "" {
+				return fmt.Errorf("placeholder %s does not take a name", match[0])
+			}
+		case "path":
+			if !hasPathParam(endpointPath, name) {
+				return fmt.Errorf("placeholder %s refers to unknown path parameter %s", match[0], name)
+			}
+		case "query", "header", "claims":
+			if name == "" {
+				return fmt.Errorf("placeholder %s requires a name", match[0])
+			}
+		default:
+			return fmt.Errorf("placeholder %s has unknown source %s", match[0], source)
+		}
+	}
+	return nil
+}
+
+// hasPathParam reports whether an endpoint path has a parameter, with or without a pattern
+func hasPathParam(endpointPath, name string) bool {
+	return strings.Contains(endpointPath, "{"+name+"}") || strings.Contains(endpointPath, "{"+name+":")
+}
+
+// isValidPath reports whether a dot-notation path is empty or has no empty segments
+func isValidPath(path string) bool {
+	if path == "" {
+		return true
+	}
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Config) validateJoin(endpointName string, j int, backend Backend) error {
+	if backend.Group != "" || backend.Concat != "" {
+		return fmt.Errorf("endpoint %s, backend %d: join cannot be combined with group or concat", endpointName, j)
+	}
+
+	if backend.Join.Path == "" {
+		return fmt.Errorf("endpoint %s, backend %d: join path is required", endpointName, j)
+	}
+
+	if backend.Join.Key == "" {
+		return fmt.Errorf("endpoint %s, backend %d: join key is required", endpointName, j)
+	}
+
+	if backend.Join.Type != "left" && backend.Join.Type != "inner" {
+		return fmt.Errorf("endpoint %s, backend %d: invalid join type %s", endpointName, j, backend.Join.Type)
+	}
+
+	return nil
+}
+
+func (c *Config) validateCollections(endpoint Endpoint) error {
+	for j, operation := range endpoint.Collections {
+		if err := c.validateCollection(endpoint.Endpoint, j, operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateCollection(endpointName string, j int, operation CollectionOperation) error {
+	kinds := 0
+	if len(operation.Sort) > 0 {
+		kinds++
+	}
+	if operation.Dedupe != "" {
+		kinds++
+	}
+	if operation.Filter != nil {
+		kinds++
+	}
+	if operation.Limit != 0 || operation.Offset != 0 {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("endpoint %s, collection %d: exactly one of sort, dedupe, filter or limit/offset is required",
+			endpointName, j)
+	}
+
+	if operation.Limit < 0 || operation.Offset < 0 {
+		return fmt.Errorf("endpoint %s, collection %d: limit and offset must not be negative", endpointName, j)
+	}
+
+	for _, field := range operation.Sort {
+		if field.Field == "" {
+			return fmt.Errorf("endpoint %s, collection %d: sort field is required", endpointName, j)
+		}
+		if field.Order != "asc" && field.Order != "desc" {
+			return fmt.Errorf("endpoint %s, collection %d: invalid sort order %s", endpointName, j, field.Order)
+		}
+	}
+
+	if operation.Filter != nil {
+		if operation.Filter.Field == "" {
+			return fmt.Errorf("endpoint %s, collection %d: filter field is required", endpointName, j)
+		}
+		if !c.getValidFilterOperators()[operation.Filter.Operator] {
+			return fmt.Errorf("endpoint %s, collection %d: invalid filter operator %s",
+				endpointName, j, operation.Filter.Operator)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) getValidFilterOperators() map[string]bool {
+	return map[string]bool{
+		"eq": true, "ne": true, "gt": true, "gte": true, "lt": true, "lte": true,
+		"in": true, "nin": true, "contains": true, "exists": true,
+	}
+}
+
+func (c *Config) validateMerge(endpoint Endpoint) error {
+	if err := c.validateMergePolicy(endpoint, "", endpoint.Merge.MergePolicy); err != nil {
+		return err
+	}
+
+	for _, override := range endpoint.Merge.Paths {
+		if override.Path == "" {
+			return fmt.Errorf("endpoint %s: merge path is required", endpoint.Endpoint)
+		}
+		if err := c.validateMergePolicy(endpoint, override.Path, override.MergePolicy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateMergePolicy(endpoint Endpoint, path string, policy MergePolicy) error {
+	location := fmt.Sprintf("endpoint %s", endpoint.Endpoint)
+	if path != "" {
+		location = fmt.Sprintf("endpoint %s, merge path %s", endpoint.Endpoint, path)
+	}
+
+	switch policy.Strategy {
+	case "", "last_wins", "first_wins", "error":
+	case "prefer_backend":
+		if policy.PreferBackend == "" {
+			return fmt.Errorf("%s: prefer_backend is required for the prefer_backend strategy", location)
+		}
+		if !c.hasNamedBackend(endpoint, policy.PreferBackend) {
+			return fmt.Errorf("%s: unknown backend %s in prefer_backend", location, policy.PreferBackend)
+		}
+	default:
+		return fmt.Errorf("%s: invalid merge strategy %s", location, policy.Strategy)
+	}
+
+	switch policy.Arrays {
+	case "", "append", "replace", "union":
+	default:
+		return fmt.Errorf("%s: invalid array merge mode %s", location, policy.Arrays)
+	}
+
+	return nil
+}
+
+func (c *Config) hasNamedBackend(endpoint Endpoint, name string) bool {
+	for _, backend := range endpoint.Backends {
+		if backend.Name == name {
+			return true
+		}
+	}
+	return false
+}
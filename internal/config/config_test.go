@@ -4,12 +4,18 @@
 package config
 
 import (
+	"crypto/tls"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/TrueTickets/api-aggregator/internal/testcert"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -157,9 +163,442 @@ endpoints:
 	// Check backend defaults
 	assert.Equal(t, "json", cfg.Endpoints[0].Backends[0].Encoding)
 	assert.Equal(t, "/test", cfg.Endpoints[0].Backends[0].URLPattern) // Should default to endpoint path
+	assert.Equal(t, "last_wins", cfg.Endpoints[0].Merge.Strategy)
+	assert.Equal(t, "append", cfg.Endpoints[0].Merge.Arrays)
 	assert.Empty(t, cfg.Endpoints[0].Backends[0].RemoveHeaders)
 }
 
+func TestTransportFor(t *testing.T) {
+	configYAML := `
+transport:
+  max_idle_conns_per_host: 32
+  disable_compression: true
+host_transports:
+  "http://users.example.com":
+    max_conns_per_host: 10
+    http2: false
+  "orders.example.com:8443":
+    idle_conn_timeout: 30s
+    tls:
+      server_name: "orders.internal"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://users.example.com"
+        connect_timeout: 2s
+      - host: "https://orders.example.com:8443"
+      - host: "https://orders.example.com:8443"
+        tls:
+          min_version: "1.3"
+`
+
+	tmpFile, err := os.CreateTemp("", "config_test_*.yaml")
+	require.NoError(t, err)
+	defer func() {
+		if removeErr := os.Remove(tmpFile.Name()); removeErr != nil {
+			t.Logf("Failed to remove temp file: %v", removeErr)
+		}
+	}()
+
+	_, err = tmpFile.WriteString(configYAML)
+	require.NoError(t, err)
+	require.NoError(t, tmpFile.Close())
+
+	cfg, err := LoadConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	users := cfg.TransportFor(cfg.Endpoints[0].Backends[0])
+	assert.Equal(t, 32, users.MaxIdleConnsPerHost)
+	assert.Equal(t, 256, users.MaxIdleConns)
+	assert.Equal(t, 10, users.MaxConnsPerHost)
+	require.NotNil(t, users.HTTP2)
+	assert.False(t, *users.HTTP2)
+	require.NotNil(t, users.DisableCompression)
+	assert.True(t, *users.DisableCompression)
+	assert.Equal(t, 2*time.Second, users.DialTimeout)
+
+	orders := cfg.TransportFor(cfg.Endpoints[0].Backends[1])
+	assert.Equal(t, 30*time.Second, orders.IdleConnTimeout)
+	assert.Equal(t, 0, orders.MaxConnsPerHost)
+	assert.Equal(t, 30*time.Second, orders.DialTimeout)
+	require.NotNil(t, orders.TLS)
+	assert.Equal(t, "orders.internal", orders.TLS.ServerName)
+
+	// Backend TLS settings replace the host TLS settings
+	ordersTLS13 := cfg.TransportFor(cfg.Endpoints[0].Backends[2])
+	require.NotNil(t, ordersTLS13.TLS)
+	assert.Equal(t, "1.3", ordersTLS13.TLS.MinVersion)
+	assert.Empty(t, ordersTLS13.TLS.ServerName)
+}
+
+func TestServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	cert := testcert.Write(t, dir, "server")
+
+	configYAML := fmt.Sprintf(`
+tls:
+  cert_file: %q
+  key_file: %q
+  client_ca_file: %q
+  min_version: "1.3"
+  cipher_suites: ["TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"]
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`, cert.CertFile, cert.KeyFile, cert.CertFile)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+	assert.True(t, cfg.TLS.Enabled())
+	assert.True(t, cfg.HTTP2Enabled())
+	assert.Equal(t, "require_and_verify", cfg.TLS.ClientAuth)
+
+	tlsConfig, err := cfg.TLS.ServerConfig()
+	require.NoError(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+
+	// Unknown cipher suites are rejected
+	cfg.TLS.CipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"}
+	_, err = cfg.TLS.ServerConfig()
+	assert.ErrorContains(t, err, "unsupported cipher suite")
+}
+
+func TestLoadConfigWithOptions_Offline(t *testing.T) {
+	configYAML := `
+tls:
+  cert_file: /missing/server.pem
+  key_file: /missing/server-key.pem
+  min_version: %q
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "https://example.com"
+        tls:
+          ca_file: /missing/ca.pem
+        auth:
+          type: hmac
+          secret:
+            file: /missing/secret
+          algorithm: sha256
+      - host: "https://example.org"
+        group: org
+        auth:
+          type: bearer
+          token:
+            value: ${file:/missing/token}
+`
+	tests := []struct {
+		name       string
+		minVersion string
+		offline    bool
+		errorMsg   string
+	}{
+		{
+			name:       "online",
+			minVersion: "1.2",
+			errorMsg:   "failed to read ${file:/missing/token}",
+		},
+		{
+			name:       "offline",
+			minVersion: "1.2",
+			offline:    true,
+		},
+		{
+			name:       "offline with an invalid TLS setting",
+			minVersion: "1.9",
+			offline:    true,
+			errorMsg:   "tls: invalid TLS version 1.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(configYAML, tt.minVersion)), 0o600))
+
+			cfg, err := LoadConfigWithOptions(configFile, LoadOptions{Offline: tt.offline})
+			if tt.errorMsg != "" {
+				assert.ErrorContains(t, err, tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			// File references are kept as they are
+			assert.Equal(t, "${file:/missing/token}", cfg.Endpoints[0].Backends[1].Auth.Token.Value)
+		})
+	}
+}
+
+func TestFallbackDefaults(t *testing.T) {
+	configYAML := `
+endpoints:
+  - endpoint: "/users/{id}"
+    encoding: xml
+    backends:
+      - host: "http://primary.example.com"
+        timeout: 2s
+        fallback:
+          - host: "http://replica.example.com"
+          - host: "http://archive.example.com"
+            url_pattern: "/archive/users/{id}"
+            encoding: json
+`
+
+	tmpFile, err := os.CreateTemp("", "config_test_*.yaml")
+	require.NoError(t, err)
+	defer func() {
+		if removeErr := os.Remove(tmpFile.Name()); removeErr != nil {
+			t.Logf("Failed to remove temp file: %v", removeErr)
+		}
+	}()
+
+	_, err = tmpFile.WriteString(configYAML)
+	require.NoError(t, err)
+	require.NoError(t, tmpFile.Close())
+
+	cfg, err := LoadConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	fallbacks := cfg.Endpoints[0].Backends[0].Fallback
+	require.Len(t, fallbacks, 2)
+	assert.Equal(t, "/users/{id}", fallbacks[0].URLPattern)
+	assert.Equal(t, "xml", fallbacks[0].Encoding)
+	assert.Equal(t, "/archive/users/{id}", fallbacks[1].URLPattern)
+	assert.Equal(t, "json", fallbacks[1].Encoding)
+}
+
+func TestJoinDefaults(t *testing.T) {
+	configYAML := `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://stats.example.com"
+        join:
+          path: "users"
+          key: "id"
+`
+
+	tmpFile, err := os.CreateTemp("", "config_test_*.yaml")
+	require.NoError(t, err)
+	defer func() {
+		if removeErr := os.Remove(tmpFile.Name()); removeErr != nil {
+			t.Logf("Failed to remove temp file: %v", removeErr)
+		}
+	}()
+
+	_, err = tmpFile.WriteString(configYAML)
+	require.NoError(t, err)
+	require.NoError(t, tmpFile.Close())
+
+	cfg, err := LoadConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	join := cfg.Endpoints[0].Backends[0].Join
+	require.NotNil(t, join)
+	assert.Equal(t, "id", join.ForeignKey)
+	assert.Equal(t, "left", join.Type)
+}
+
+func TestAuthDefaults(t *testing.T) {
+	t.Setenv("API_AGGREGATOR_TEST_TOKEN", "token")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("shared\n"), 0o600))
+
+	configYAML := fmt.Sprintf(`
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users.example.com"
+        auth:
+          type: "api_key"
+          token:
+            env: "API_AGGREGATOR_TEST_TOKEN"
+      - host: "http://orders.example.com"
+        auth:
+          type: "hmac"
+          secret:
+            file: %q
+`, secretFile)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	apiKey := cfg.Endpoints[0].Backends[0].Auth
+	require.NotNil(t, apiKey)
+	assert.Equal(t, "X-API-Key", apiKey.Header)
+
+	signing := cfg.Endpoints[0].Backends[1].Auth
+	require.NotNil(t, signing)
+	assert.Equal(t, "X-Signature", signing.Header)
+	assert.Equal(t, "sha256", signing.Algorithm)
+
+	secret, err := signing.Secret.Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "shared", secret)
+}
+
+func TestExposeHeaderDefaults(t *testing.T) {
+	configYAML := `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users.example.com"
+        expose_headers:
+          - name: "cache-control"
+          - name: "Set-Cookie"
+          - name: "ETag"
+          - name: "X-RateLimit-Remaining"
+            merge: "min"
+`
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	expose := cfg.Endpoints[0].Backends[0].ExposeHeaders
+	require.Len(t, expose, 4)
+	assert.Equal(t, "cache_control", expose[0].Merge)
+	assert.Equal(t, "append", expose[1].Merge)
+	assert.Equal(t, "first", expose[2].Merge)
+	assert.Equal(t, "min", expose[3].Merge)
+}
+
+func TestRequestBodyDefaults(t *testing.T) {
+	configYAML := `
+endpoints:
+  - endpoint: "/users/{id}"
+    method: POST
+    backends:
+      - host: "http://users.example.com"
+        encoding: xml
+        body:
+          target: "user"
+          set:
+            id: "{path.id}"
+            source:
+              system: "aggregator"
+            version: 2
+`
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	body := cfg.Endpoints[0].Backends[0].Body
+	require.NotNil(t, body)
+	assert.Equal(t, "request", body.Root)
+	assert.Equal(t, "{path.id}", body.Set["id"])
+	assert.Equal(t, map[string]interface{}{"system": "aggregator"}, body.Set["source"])
+	assert.Equal(t, 2, body.Set["version"])
+}
+
+func TestBackendMethodDefaults(t *testing.T) {
+	configYAML := `
+endpoints:
+  - endpoint: "/checkout"
+    method: POST
+    backends:
+      - host: "http://orders.example.com"
+      - host: "http://cart.example.com"
+        method: get
+        hedge:
+          delay: 50ms
+`
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "POST", cfg.Endpoints[0].Backends[0].Method)
+	assert.Equal(t, "GET", cfg.Endpoints[0].Backends[1].Method)
+}
+
+func TestOpenAPIDefaults(t *testing.T) {
+	configYAML := `
+openapi:
+  enabled: true
+endpoints:
+  - endpoint: "/users/{id}"
+    summary: Get a user
+    tags: [users]
+    response_schema:
+      type: object
+    backends:
+      - host: "http://users.example.com"
+`
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/openapi.json", cfg.OpenAPI.Path)
+	assert.Equal(t, "Get a user", cfg.Endpoints[0].Summary)
+	assert.Equal(t, []string{"users"}, cfg.Endpoints[0].Tags)
+	assert.Equal(t, map[string]interface{}{"type": "object"}, cfg.Endpoints[0].ResponseSchema)
+}
+
+func TestRequestValidationConfig(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "order.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{"type": "object", "required": ["sku"]}`), 0o600))
+
+	configYAML := `
+endpoints:
+  - endpoint: "/orders/{id}"
+    method: POST
+    request_schema_file: "` + schemaFile + `"
+    path_params:
+      id:
+        type: uuid
+    query_params:
+      channel:
+        enum: [web, mobile]
+    backends:
+      - host: "http://orders.example.com"
+        response_schema_file: "` + schemaFile + `"
+`
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	endpoint := cfg.Endpoints[0]
+	assert.Equal(t, "uuid", endpoint.PathParams["id"].Type)
+	assert.Equal(t, "string", endpoint.QueryParams["channel"].Type)
+
+	document, err := endpoint.LoadRequestSchema()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "object", "required": []interface{}{"sku"}}, document)
+
+	// Backend response schemas are enforced by default
+	backend := endpoint.Backends[0]
+	assert.Equal(t, "enforce", backend.ResponseSchemaMode)
+	document, err = backend.LoadResponseSchema()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "object", "required": []interface{}{"sku"}}, document)
+}
+
 func TestLoadConfigFromEnv(t *testing.T) {
 	// Create a temporary config file
 	configYAML := `
@@ -301,29 +740,953 @@ endpoints:
 			expectError: true,
 			errorMsg:    "host is required",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tmpFile, err := os.CreateTemp("", "config_test_*.yaml")
-			require.NoError(t, err)
-			defer func() {
-				if removeErr := os.Remove(tmpFile.Name()); removeErr != nil {
-					t.Logf("Failed to remove temp file: %v", removeErr)
-				}
-			}()
-
-			_, err = tmpFile.WriteString(tt.configYAML)
-			require.NoError(t, err)
-			require.NoError(t, tmpFile.Close())
-
-			_, err = LoadConfig(tmpFile.Name())
-			if tt.expectError {
-				require.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errorMsg)
-			} else {
-				require.NoError(t, err)
-			}
-		})
-	}
+		{
+			name: "valid collection operations",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        concat: "items"
+    collections:
+      - path: "items"
+        dedupe: "id"
+      - path: "items"
+        sort:
+          - field: "id"
+            order: desc
+          - field: "title"
+      - path: "items"
+        filter:
+          field: "completed"
+          operator: eq
+          value: false
+      - path: "items"
+        limit: 10
+`,
+			expectError: false,
+		},
+		{
+			name: "collection operation with multiple kinds",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    collections:
+      - path: "items"
+        dedupe: "id"
+        limit: 5
+`,
+			expectError: true,
+			errorMsg:    "exactly one of sort, dedupe, filter or limit/offset is required",
+		},
+		{
+			name: "collection operation with invalid sort order",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    collections:
+      - path: "items"
+        sort:
+          - field: "id"
+            order: up
+`,
+			expectError: true,
+			errorMsg:    "invalid sort order up",
+		},
+		{
+			name: "collection operation with invalid filter operator",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    collections:
+      - path: "items"
+        filter:
+          field: "id"
+          operator: like
+`,
+			expectError: true,
+			errorMsg:    "invalid filter operator like",
+		},
+		{
+			name: "valid join",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users.example.com"
+        group: "users"
+      - host: "http://stats.example.com"
+        join:
+          path: "users"
+          key: "id"
+          foreign_key: "user_id"
+          type: inner
+`,
+			expectError: false,
+		},
+		{
+			name: "join without key",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://stats.example.com"
+        join:
+          path: "users"
+`,
+			expectError: true,
+			errorMsg:    "join key is required",
+		},
+		{
+			name: "join combined with group",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://stats.example.com"
+        group: "stats"
+        join:
+          path: "users"
+          key: "id"
+`,
+			expectError: true,
+			errorMsg:    "join cannot be combined with group or concat",
+		},
+		{
+			name: "join with invalid type",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://stats.example.com"
+        join:
+          path: "users"
+          key: "id"
+          type: outer
+`,
+			expectError: true,
+			errorMsg:    "invalid join type outer",
+		},
+		{
+			name: "fallback without host",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        timeout: 2s
+        fallback:
+          - url_pattern: "/replica"
+`,
+			expectError: true,
+			errorMsg:    "backend 0, fallback 0: host is required",
+		},
+		{
+			name: "fallback without backend timeout",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        fallback:
+          - host: "http://replica.example.com"
+`,
+			expectError: true,
+			errorMsg:    "backend 0: fallback requires a backend timeout",
+		},
+		{
+			name: "hedging on idempotent method",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: GET
+    backends:
+      - host: "http://example.com"
+        hedge:
+          delay: 50ms
+          hosts:
+            - "http://replica.example.com"
+`,
+			expectError: false,
+		},
+		{
+			name: "hedging on non-idempotent method",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    backends:
+      - host: "http://example.com"
+        hedge:
+          adaptive: true
+`,
+			expectError: true,
+			errorMsg:    "hedging requires an idempotent method, got POST",
+		},
+		{
+			name: "per-backend timeouts and soft timeout",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    timeout: 2s
+    soft_timeout: 150ms
+    backends:
+      - host: "http://example.com"
+        timeout: 500ms
+        connect_timeout: 100ms
+        tls_handshake_timeout: 200ms
+        response_header_timeout: 300ms
+      - host: "http://recommendations.example.com"
+        optional: true
+`,
+			expectError: false,
+		},
+		{
+			name: "negative backend timeout",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        timeout: -1s
+`,
+			expectError: true,
+			errorMsg:    "timeouts must not be negative",
+		},
+		{
+			name: "valid header rules",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{id:[0-9]+}"
+    backends:
+      - host: "http://example.com"
+        forward_headers: ["Authorization", "X-Tenant"]
+        rename_headers:
+          X-Tenant: X-Org
+        set_headers:
+          X-User-Id: "{path.id}"
+          X-Request-Id: "{request_id}"
+        add_headers:
+          X-Forwarded-Sub: "{claims.sub}"
+`,
+			expectError: false,
+		},
+		{
+			name: "invalid header name",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        set_headers:
+          "X Bad": "value"
+`,
+			expectError: true,
+			errorMsg:    "invalid header name",
+		},
+		{
+			name: "header template with unknown path parameter",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{id}"
+    backends:
+      - host: "http://example.com"
+        set_headers:
+          X-User: "{path.user}"
+`,
+			expectError: true,
+			errorMsg:    "unknown path parameter user",
+		},
+		{
+			name: "header template with unknown source",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        add_headers:
+          X-Value: "{cookie.session}"
+`,
+			expectError: true,
+			errorMsg:    "unknown source cookie",
+		},
+		{
+			name: "valid expose headers and response headers",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    response_headers:
+      X-Frame-Options: "DENY"
+    backends:
+      - host: "http://example.com"
+        expose_headers:
+          - name: "Cache-Control"
+          - name: "X-RateLimit-*"
+            merge: "min"
+          - name: "ETag"
+            as: "X-Users-ETag"
+`,
+			expectError: false,
+		},
+		{
+			name: "expose etag with several backends",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://users"
+        expose_headers:
+          - name: "ETag"
+      - host: "http://orders"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test, backend 0: expose header ETag: must be renamed with as when the endpoint has several backends",
+		},
+		{
+			name: "expose controlled header",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        expose_headers:
+          - name: "Content-Type"
+`,
+			expectError: true,
+			errorMsg:    "header is set by the service",
+		},
+		{
+			name: "expose prefix with rename",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        expose_headers:
+          - name: "X-RateLimit-*"
+            as: "X-Limit"
+`,
+			expectError: true,
+			errorMsg:    "as cannot be used with a prefix match",
+		},
+		{
+			name: "invalid expose merge",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        expose_headers:
+          - name: "ETag"
+            merge: "average"
+`,
+			expectError: true,
+			errorMsg:    "invalid merge average",
+		},
+		{
+			name: "static aggregation response header",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    response_headers:
+      X-API-Aggregation-Completed: "true"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "response header X-API-Aggregation-Completed cannot be set",
+		},
+		{
+			name: "invalid auth type",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        auth:
+          type: "digest"
+`,
+			expectError: true,
+			errorMsg:    "auth: invalid type digest",
+		},
+		{
+			name: "auth token from unset environment variable",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        auth:
+          type: "bearer"
+          token:
+            env: "API_AGGREGATOR_TEST_UNSET_TOKEN"
+`,
+			expectError: true,
+			errorMsg:    "API_AGGREGATOR_TEST_UNSET_TOKEN is not set",
+		},
+		{
+			name: "oauth2 without client secret",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        auth:
+          type: "oauth2"
+          token_url: "https://auth.example.com/token"
+          client_id: "aggregator"
+`,
+			expectError: true,
+			errorMsg:    "token_url, client_id and client_secret are required",
+		},
+		{
+			name: "invalid hmac algorithm",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        auth:
+          type: "hmac"
+          secret:
+            value: "shared"
+          algorithm: "md5"
+`,
+			expectError: true,
+			errorMsg:    "invalid hmac algorithm md5",
+		},
+		{
+			name: "valid auth providers",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        auth:
+          type: "api_key"
+          token:
+            value: "key"
+      - host: "http://example.com"
+        auth:
+          type: "basic"
+          username: "user"
+          password:
+            value: "pass"
+      - host: "http://example.com"
+        auth:
+          type: "hmac"
+          key_id: "aggregator"
+          secret:
+            value: "shared"
+`,
+			expectError: false,
+		},
+		{
+			name: "h2c with listener tls",
+			configYAML: `
+h2c: true
+tls:
+  cert_file: "server.pem"
+  key_file: "server-key.pem"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "h2c cannot be combined with tls",
+		},
+		{
+			name: "h2c without http2",
+			configYAML: `
+h2c: true
+http2: false
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "h2c requires http2",
+		},
+		{
+			name: "listener tls key without cert",
+			configYAML: `
+tls:
+  key_file: "server-key.pem"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "tls: cert_file is required",
+		},
+		{
+			name: "invalid listener client auth",
+			configYAML: `
+tls:
+  cert_file: "server.pem"
+  key_file: "server-key.pem"
+  client_auth: "always"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "invalid client_auth always",
+		},
+		{
+			name: "listener client verification without CA",
+			configYAML: `
+tls:
+  cert_file: "server.pem"
+  key_file: "server-key.pem"
+  client_auth: "require_and_verify"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "requires client_ca_file",
+		},
+		{
+			name: "tls cert without key",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "https://example.com"
+        tls:
+          cert_file: "client.pem"
+`,
+			expectError: true,
+			errorMsg:    "cert_file and key_file must be set together",
+		},
+		{
+			name: "tls missing CA file",
+			configYAML: `
+host_transports:
+  "example.com":
+    tls:
+      ca_file: "/nonexistent/ca.pem"
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "https://example.com"
+`,
+			expectError: true,
+			errorMsg:    "failed to read CA file",
+		},
+		{
+			name: "invalid tls min version",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "https://example.com"
+        tls:
+          min_version: "1.4"
+`,
+			expectError: true,
+			errorMsg:    "invalid TLS version 1.4",
+		},
+		{
+			name: "nested group and concat paths",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        group: "event.venue"
+      - host: "http://example.com"
+        concat: "feed.items"
+`,
+			expectError: false,
+		},
+		{
+			name: "group path with empty segment",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        group: "event..venue"
+`,
+			expectError: true,
+			errorMsg:    "group and concat paths must not contain empty segments",
+		},
+		{
+			name: "valid merge policy",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - name: "primary"
+        host: "http://primary.example.com"
+      - host: "http://secondary.example.com"
+    merge:
+      strategy: prefer_backend
+      prefer_backend: primary
+      arrays: union
+      record_conflicts: true
+      paths:
+        - path: "items"
+          arrays: union
+          array_key: "id"
+`,
+			expectError: false,
+		},
+		{
+			name: "prefer unknown backend",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    merge:
+      strategy: prefer_backend
+      prefer_backend: missing
+`,
+			expectError: true,
+			errorMsg:    "unknown backend missing in prefer_backend",
+		},
+		{
+			name: "invalid merge strategy",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    merge:
+      strategy: random
+`,
+			expectError: true,
+			errorMsg:    "invalid merge strategy random",
+		},
+		{
+			name: "invalid array merge mode in path override",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+    merge:
+      paths:
+        - path: "items"
+          arrays: zip
+`,
+			expectError: true,
+			errorMsg:    "merge path items: invalid array merge mode zip",
+		},
+		{
+			name: "request body on GET endpoint",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        body:
+          target: "user"
+`,
+			expectError: true,
+			errorMsg:    "body requires a POST, PUT or PATCH method, got GET",
+		},
+		{
+			name: "request body on GET backend of POST endpoint",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    backends:
+      - host: "http://example.com"
+        method: get
+        body:
+          target: "user"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test, backend 0: body requires a POST, PUT or PATCH method, got GET",
+		},
+		{
+			name: "invalid backend method",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        method: FETCH
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test, backend 0: invalid method FETCH",
+		},
+		{
+			name: "request schema on GET endpoint",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    request_schema:
+      type: object
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test: request_schema requires a POST, PUT or PATCH method, got GET",
+		},
+		{
+			name: "unsupported request schema keyword",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    request_schema:
+      type: object
+      properties:
+        name:
+          type: string
+          contentMediaType: text/plain
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test: request_schema: schema /properties/name/contentMediaType: unsupported keyword",
+		},
+		{
+			name: "missing request schema file",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    request_schema_file: "/nonexistent/schema.json"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /test: request_schema: failed to read schema file",
+		},
+		{
+			name: "constraint on unknown path parameter",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{id}"
+    path_params:
+      user_id:
+        type: int
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /users/{id}: path_params: unknown path parameter user_id",
+		},
+		{
+			name: "invalid parameter type",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    query_params:
+      limit:
+        type: integer
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /users: query_params limit: invalid type integer",
+		},
+		{
+			name: "invalid parameter pattern",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    query_params:
+      sort:
+        pattern: "(name"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "endpoint /users: query_params sort: invalid pattern",
+		},
+		{
+			name: "invalid response schema mode",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://example.com"
+        response_schema:
+          type: object
+        response_schema_mode: strict
+`,
+			expectError: true,
+			errorMsg:    "endpoint /users, backend 0: invalid response_schema_mode strict",
+		},
+		{
+			name: "invalid response schema",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://example.com"
+        response_schema:
+          type: [object, map]
+`,
+			expectError: true,
+			errorMsg:    "endpoint /users, backend 0: response_schema: schema /type: invalid type map",
+		},
+		{
+			name: "openapi path without leading slash",
+			configYAML: `
+openapi:
+  enabled: true
+  path: openapi.json
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "openapi: invalid path openapi.json",
+		},
+		{
+			name: "openapi path reserved for health checks",
+			configYAML: `
+openapi:
+  enabled: true
+  path: /livez
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "openapi: path /livez is reserved for health checks",
+		},
+		{
+			name: "openapi path conflicts with endpoint",
+			configYAML: `
+openapi:
+  enabled: true
+endpoints:
+  - endpoint: "/openapi.json"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: true,
+			errorMsg:    "openapi: path /openapi.json conflicts with endpoint /openapi.json",
+		},
+		{
+			name: "openapi path of disabled document is not checked",
+			configYAML: `
+openapi:
+  path: /livez
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://example.com"
+`,
+			expectError: false,
+		},
+		{
+			name: "hedging on POST backend of GET endpoint",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    backends:
+      - host: "http://example.com"
+        method: POST
+        hedge:
+          delay: 50ms
+`,
+			expectError: true,
+			errorMsg:    "hedging requires an idempotent method, got POST",
+		},
+		{
+			name: "request body template with unknown path parameter",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    backends:
+      - host: "http://example.com"
+        body:
+          set:
+            id: "{path.id}"
+`,
+			expectError: true,
+			errorMsg:    "body: set id:",
+		},
+		{
+			name: "request body with empty path segment",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    backends:
+      - host: "http://example.com"
+        body:
+          allow: ["user..name"]
+`,
+			expectError: true,
+			errorMsg:    `body: path "user..name" must not contain empty segments`,
+		},
+		{
+			name: "invalid XML root",
+			configYAML: `
+endpoints:
+  - endpoint: "/test"
+    method: POST
+    backends:
+      - host: "http://example.com"
+        encoding: xml
+        body:
+          root: "1request"
+`,
+			expectError: true,
+			errorMsg:    "body: invalid root element name 1request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config_test_*.yaml")
+			require.NoError(t, err)
+			defer func() {
+				if removeErr := os.Remove(tmpFile.Name()); removeErr != nil {
+					t.Logf("Failed to remove temp file: %v", removeErr)
+				}
+			}()
+
+			_, err = tmpFile.WriteString(tt.configYAML)
+			require.NoError(t, err)
+			require.NoError(t, tmpFile.Close())
+
+			_, err = LoadConfig(tmpFile.Name())
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecretMarshalYAML(t *testing.T) {
+	data, err := yaml.Marshal(Auth{
+		Type:     "basic",
+		Password: Secret{Value: "hunter2"},
+		Token:    Secret{Env: "API_TOKEN"},
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "hunter2")
+	assert.Contains(t, string(data), "value: REDACTED")
+	assert.Contains(t, string(data), "env: API_TOKEN")
 }
@@ -0,0 +1,371 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Top-level keys merged across configuration files rather than set in one of them
+const (
+	endpointsKey        = "endpoints"
+	hostTransportsKey   = "host_transports"
+	upstreamsKey        = "upstreams"
+	backendTemplatesKey = "backend_templates"
+	includeKey          = "include"
+)
+
+// namedSettings are the top-level maps merged across configuration files by name, with the
+// description of their entries in errors
+var namedSettings = map[string]string{
+	hostTransportsKey:   "host transport",
+	upstreamsKey:        "upstream",
+	backendTemplatesKey: "backend template",
+}
+
+// configFile is a parsed configuration file
+type configFile struct {
+	path string
+	cfg  Config
+
+	// Top-level keys set in the file
+	keys []string
+}
+
+// configLoader reads a set of configuration files, following includes
+type configLoader struct {
+	files []configFile
+
+	// Files already read, to reject a file loaded twice or included in a cycle
+	seen map[string]bool
+
+	// Leave file references unexpanded, see LoadOptions
+	offline bool
+}
+
+// expandConfigPath returns the files of a configuration path in sorted order: the file itself,
+// the YAML files of a directory, or the files matching a glob pattern
+func expandConfigPath(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config path pattern %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no config files match %s", path)
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files in directory %s", path)
+	}
+	return files, nil
+}
+
+// load reads the files of a configuration path; files included by a file are read right after it
+func (l *configLoader) load(path string) error {
+	files, err := expandConfigPath(path)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		absolute, err := filepath.Abs(file)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if l.seen[absolute] {
+			return fmt.Errorf("config file %s is loaded more than once", file)
+		}
+		l.seen[absolute] = true
+
+		parsed, err := parseConfigFile(file, l.offline)
+		if err != nil {
+			return err
+		}
+		l.files = append(l.files, parsed)
+
+		// Includes are relative to the including file
+		for _, include := range parsed.cfg.Include {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(file), include)
+			}
+			if err := l.load(include); err != nil {
+				return fmt.Errorf("%s: include: %w", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseConfigFile reads, interpolates and decodes a configuration file
+func parseConfigFile(path string, offline bool) (configFile, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Config files are provided by the operator
+	if err != nil {
+		return configFile{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return configFile{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	secrets, err := interpolate(&root, path, offline)
+	if err != nil {
+		return configFile{}, fmt.Errorf("failed to interpolate config file: %w", err)
+	}
+
+	file := configFile{path: path}
+	// An empty file has no document to decode
+	if root.Kind != 0 {
+		if err := root.Decode(&file.cfg); err != nil {
+			return configFile{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if document := root.Content[0]; document.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(document.Content); i += 2 {
+				file.keys = append(file.keys, document.Content[i].Value)
+			}
+		}
+	}
+	file.cfg.secrets = secrets
+
+	// Unknown keys are usually typos; they are only reported by Lint when allowed
+	for _, field := range findUnknownFields(&root, reflect.TypeOf(file.cfg)) {
+		file.cfg.unknownFields = append(file.cfg.unknownFields, field.format(path))
+	}
+
+	for i := range file.cfg.Endpoints {
+		file.cfg.Endpoints[i].source = path
+	}
+	recordBackendKeys(&file.cfg, &root)
+	return file, nil
+}
+
+// recordBackendKeys records the keys each backend and backend template sets in a file, so
+// templates only fill the fields a backend leaves out
+func recordBackendKeys(cfg *Config, root *yaml.Node) {
+	if root.Kind != yaml.DocumentNode {
+		return
+	}
+	document := root.Content[0]
+
+	endpoints := mappingValue(document, endpointsKey)
+	for i, endpoint := range sequenceItems(endpoints) {
+		if i >= len(cfg.Endpoints) {
+			break
+		}
+		backends := cfg.Endpoints[i].Backends
+		for j, backend := range sequenceItems(mappingValue(endpoint, "backends")) {
+			if j < len(backends) {
+				backends[j].setFields = mappingKeys(backend)
+			}
+		}
+	}
+
+	templates := mappingValue(document, backendTemplatesKey)
+	for name, template := range cfg.BackendTemplates {
+		template.setFields = mappingKeys(mappingValue(templates, name))
+		cfg.BackendTemplates[name] = template
+	}
+}
+
+// mappingValue returns the value of a key in a YAML mapping or the mappings merged into it
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].ShortTag() != mergeTag {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+			continue
+		}
+		for _, merged := range mergedMappings(node.Content[i+1]) {
+			if value := mappingValue(merged, key); value != nil {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of a YAML mapping, including those of the mappings merged into it
+func mappingKeys(node *yaml.Node) map[string]bool {
+	keys := make(map[string]bool)
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return keys
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].ShortTag() != mergeTag {
+			keys[node.Content[i].Value] = true
+			continue
+		}
+		for _, merged := range mergedMappings(node.Content[i+1]) {
+			for key := range mappingKeys(merged) {
+				keys[key] = true
+			}
+		}
+	}
+	return keys
+}
+
+// mergedMappings returns the mappings of a << value: one mapping or a sequence of them
+func mergedMappings(node *yaml.Node) []*yaml.Node {
+	node = resolveAlias(node)
+	if node.Kind == yaml.SequenceNode {
+		return node.Content
+	}
+	return []*yaml.Node{node}
+}
+
+// sequenceItems returns the items of a YAML sequence, nil for any other node
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// resolveAlias returns the node an alias refers to, or the node itself
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// merge combines the files into one configuration. Endpoints are appended in file order, and
+// host transports, upstreams and backend templates merged by name; any other setting may only
+// be set in one file.
+func (l *configLoader) merge() (Config, error) {
+	if len(l.files) == 1 {
+		return l.files[0].cfg, nil
+	}
+
+	var merged Config
+	merged.files = make([]string, 0, len(l.files))
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	fieldIndexes := yamlFieldIndexes(mergedValue.Type())
+
+	setIn := make(map[string]string)
+	routes := make(map[string]string)
+	for _, file := range l.files {
+		merged.files = append(merged.files, file.path)
+		merged.unknownFields = append(merged.unknownFields, file.cfg.unknownFields...)
+		for _, secret := range file.cfg.secrets {
+			merged.secrets = append(merged.secrets, secret.afterEndpoints(len(merged.Endpoints)))
+		}
+
+		for _, key := range file.keys {
+			var err error
+			switch key {
+			case endpointsKey, includeKey:
+			case hostTransportsKey:
+				err = mergeNamed(&merged.HostTransports, file.cfg.HostTransports, key, file.path, setIn)
+			case upstreamsKey:
+				err = mergeNamed(&merged.Upstreams, file.cfg.Upstreams, key, file.path, setIn)
+			case backendTemplatesKey:
+				err = mergeNamed(&merged.BackendTemplates, file.cfg.BackendTemplates, key, file.path, setIn)
+			default:
+				index, known := fieldIndexes[key]
+				if !known {
+					continue
+				}
+				if other, exists := setIn[key]; exists {
+					return Config{}, fmt.Errorf("%s: %s is already set in %s", file.path, key, other)
+				}
+				setIn[key] = file.path
+				mergedValue.FieldByIndex(index).Set(reflect.ValueOf(file.cfg).FieldByIndex(index))
+			}
+			if err != nil {
+				return Config{}, err
+			}
+		}
+
+		for _, endpoint := range file.cfg.Endpoints {
+			// Defaults are not set yet
+			method := endpoint.Method
+			if method == "" {
+				method = defaultMethod
+			}
+			route := strings.ToUpper(method) + " " + endpoint.Endpoint
+			if other, exists := routes[route]; exists && other != file.path {
+				return Config{}, fmt.Errorf("%s: endpoint %s is already defined in %s", file.path, route, other)
+			}
+			routes[route] = file.path
+			merged.Endpoints = append(merged.Endpoints, endpoint)
+		}
+	}
+	merged.Include = nil
+	merged.settingFiles = setIn
+	return merged, nil
+}
+
+// mergeNamed adds the entries of a top-level map set in a file to the merged map, recording
+// the file each name is set in to reject duplicates
+func mergeNamed[V any](merged *map[string]V, entries map[string]V, key, file string, setIn map[string]string) error {
+	for _, name := range sortedNames(entries) {
+		setting := key + " " + name
+		if other, exists := setIn[setting]; exists {
+			return fmt.Errorf("%s: %s %s is already set in %s", file, namedSettings[key], name, other)
+		}
+		setIn[setting] = file
+		if *merged == nil {
+			*merged = make(map[string]V)
+		}
+		(*merged)[name] = entries[name]
+	}
+	return nil
+}
+
+// yamlFieldIndexes returns the field indexes of a struct by YAML key
+func yamlFieldIndexes(t reflect.Type) map[string][]int {
+	indexes := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlFieldName(field)
+		if field.IsExported() && name != "" && name != "-" {
+			indexes[name] = field.Index
+		}
+	}
+	return indexes
+}
+
+// yamlFieldName returns the YAML key of a struct field
+func yamlFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
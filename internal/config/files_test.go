@@ -0,0 +1,278 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFiles writes files relative to a directory
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+// endpointPaths returns the paths of the configured endpoints in order
+func endpointPaths(cfg *Config) []string {
+	var paths []string
+	for _, endpoint := range cfg.Endpoints {
+		paths = append(paths, endpoint.Method+" "+endpoint.Endpoint)
+	}
+	return paths
+}
+
+func TestLoadConfig_MultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"main.yaml": `
+timeout: 5s
+include:
+  - teams/*.yaml
+host_transports:
+  "http://users":
+    max_idle_conns: 10
+upstreams:
+  users-api:
+    host: "http://users"
+backend_templates:
+  users-service:
+    use: users-api
+    encoding: json
+endpoints:
+  - endpoint: "/health-summary"
+    backends:
+      - host: "http://status"
+`,
+		"teams/users.yaml": `
+host_transports:
+  "http://orders":
+    max_idle_conns: 20
+upstreams:
+  orders-api:
+    host: "http://orders"
+backend_templates:
+  orders-service:
+    use: orders-api
+    timeout: 2s
+endpoints:
+  - endpoint: "/users/{user}"
+    backends:
+      - host: "http://users"
+  - endpoint: "/users/{user}"
+    method: DELETE
+    backends:
+      - host: "http://users"
+`,
+		"teams/orders.yaml": `
+endpoints:
+  - endpoint: "/orders"
+    backends:
+      - use: orders-service
+`,
+		"teams/billing.yml": `
+endpoints:
+  - endpoint: "/invoices"
+    backends:
+      - host: "http://billing"
+`,
+		"teams/README.md": "not a config file",
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{
+			name:     "includes are read after the including file, sorted by name",
+			path:     filepath.Join(dir, "main.yaml"),
+			expected: []string{"GET /health-summary", "GET /orders", "GET /users/{user}", "DELETE /users/{user}"},
+		},
+		{
+			name:     "directory",
+			path:     filepath.Join(dir, "teams"),
+			expected: []string{"GET /invoices", "GET /orders", "GET /users/{user}", "DELETE /users/{user}"},
+		},
+		{
+			name:     "glob pattern",
+			path:     filepath.Join(dir, "teams", "users.*"),
+			expected: []string{"GET /users/{user}", "DELETE /users/{user}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endpointPaths(cfg))
+		})
+	}
+
+	cfg, err := LoadConfig(filepath.Join(dir, "main.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, 5*time.Second, cfg.Endpoints[1].Timeout, "global settings apply to endpoints of every file")
+	assert.Equal(t, 10, cfg.HostTransports["http://users"].MaxIdleConns)
+	assert.Equal(t, 20, cfg.HostTransports["http://orders"].MaxIdleConns)
+
+	// Upstreams and backend templates are merged by name, and used from any file
+	assert.Equal(t, []string{"orders-api", "users-api"}, sortedNames(cfg.Upstreams))
+	assert.Equal(t, []string{"orders-service", "users-service"}, sortedNames(cfg.BackendTemplates))
+	orders := cfg.Endpoints[1].Backends[0]
+	assert.Equal(t, "http://orders", orders.Host)
+	assert.Equal(t, 2*time.Second, orders.Timeout)
+}
+
+func TestLoadConfig_MultipleFilesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		path     string
+		errorMsg string
+	}{
+		{
+			name: "duplicate route across files",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /users\n    backends:\n      - host: http://a\n",
+				"b.yaml": "endpoints:\n  - endpoint: /users\n    method: get\n    backends:\n      - host: http://b\n",
+			},
+			path:     ".",
+			errorMsg: "failed to merge config files: DIR/b.yaml: endpoint GET /users is already defined in DIR/a.yaml",
+		},
+		{
+			name: "setting in two files",
+			files: map[string]string{
+				"a.yaml": "timeout: 1s\nendpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "timeout: 2s\n",
+			},
+			path:     ".",
+			errorMsg: "failed to merge config files: DIR/b.yaml: timeout is already set in DIR/a.yaml",
+		},
+		{
+			name: "host transport in two files",
+			files: map[string]string{
+				"a.yaml": "host_transports:\n  http://a:\n    max_idle_conns: 1\n",
+				"b.yaml": "host_transports:\n  http://a:\n    max_idle_conns: 2\n",
+			},
+			path:     ".",
+			errorMsg: "failed to merge config files: DIR/b.yaml: host transport http://a is already set in DIR/a.yaml",
+		},
+		{
+			name: "upstream in two files",
+			files: map[string]string{
+				"a.yaml": "upstreams:\n  users:\n    host: http://a\n  orders:\n    host: http://orders\n",
+				"b.yaml": "upstreams:\n  users:\n    host: http://b\n",
+			},
+			path:     ".",
+			errorMsg: "failed to merge config files: DIR/b.yaml: upstream users is already set in DIR/a.yaml",
+		},
+		{
+			name: "backend template in two files",
+			files: map[string]string{
+				"a.yaml": "backend_templates:\n  users:\n    host: http://a\n",
+				"b.yaml": "backend_templates:\n  users:\n    host: http://b\n",
+			},
+			path:     ".",
+			errorMsg: "failed to merge config files: DIR/b.yaml: backend template users is already set in DIR/a.yaml",
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"a.yaml": "include: [b.yaml]\n",
+				"b.yaml": "include: [a.yaml]\n",
+			},
+			path:     "a.yaml",
+			errorMsg: "DIR/a.yaml: include: DIR/b.yaml: include: config file DIR/a.yaml is loaded more than once",
+		},
+		{
+			name:     "include matching nothing",
+			files:    map[string]string{"a.yaml": "include: [teams/*.yaml]\n"},
+			path:     "a.yaml",
+			errorMsg: "DIR/a.yaml: include: no config files match DIR/teams/*.yaml",
+		},
+		{
+			name:     "directory without config files",
+			files:    map[string]string{"notes.txt": "nothing"},
+			path:     ".",
+			errorMsg: "no config files in directory DIR",
+		},
+		{
+			name: "validation error names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "endpoints:\n  - endpoint: /b\n    encoding: toml\n    backends:\n      - host: http://b\n",
+			},
+			path:     ".",
+			errorMsg: "invalid configuration: DIR/b.yaml: endpoint /b: invalid encoding toml",
+		},
+		{
+			name: "upstream validation error names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "upstreams:\n  users:\n    host: \"\"\n",
+			},
+			path:     ".",
+			errorMsg: "invalid configuration: DIR/b.yaml: upstream users: host is required",
+		},
+		{
+			name: "backend template validation error names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "backend_templates:\n  users:\n    use: missing\n",
+			},
+			path:     ".",
+			errorMsg: "invalid configuration: DIR/b.yaml: backend template users: unknown upstream missing",
+		},
+		{
+			name: "host transport validation error names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "host_transports:\n  http://a:\n    max_idle_conns: -1\n",
+			},
+			path:     ".",
+			errorMsg: "invalid configuration: DIR/b.yaml: host transport http://a: connection limits must not be negative",
+		},
+		{
+			name: "global setting validation error names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "h2c: true\nhttp2: false\n",
+			},
+			path:     ".",
+			errorMsg: "invalid configuration: DIR/b.yaml: h2c requires http2",
+		},
+		{
+			name: "unknown field names the file",
+			files: map[string]string{
+				"a.yaml": "endpoints:\n  - endpoint: /a\n    backends:\n      - host: http://a\n",
+				"b.yaml": "timout: 1s\n",
+			},
+			path:     ".",
+			errorMsg: `failed to parse config file: DIR/b.yaml:1:1: unknown field "timout", did you mean "timeout"?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+
+			path := filepath.Join(dir, tt.path)
+			_, err := LoadConfig(path)
+			require.Error(t, err)
+			assert.Equal(t, tt.errorMsg, strings.ReplaceAll(err.Error(), dir, "DIR"))
+		})
+	}
+}
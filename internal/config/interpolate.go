@@ -0,0 +1,284 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// interpolationPattern matches ${VAR}, ${VAR:-default}, ${public:VAR} and ${file:path}
+// references, and the $${ escape for a literal ${
+var interpolationPattern = regexp.MustCompile(`\$\$\{|\$\{([^{}]*)\}`)
+
+// envReferencePattern matches the body of an environment variable reference
+var envReferencePattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?::-(.*))?$`)
+
+// publicPrefix marks an environment variable reference whose value is printed in dumps
+const publicPrefix = "public:"
+
+// secretScalar is a scalar of a configuration file interpolated from a file or an environment
+// variable not marked public, redacted from dumps by its location
+type secretScalar struct {
+	// Location of the scalar, which is a mapping key when key is set
+	path yamlPath
+	key  bool
+
+	// Interpolated value, and the value with the secret references redacted
+	value    string
+	redacted string
+}
+
+// mergeTag is the tag of the << key merging mappings into the mapping holding it
+const mergeTag = "!!merge"
+
+// yamlPath locates a node of a YAML document by the mapping keys and sequence indexes leading to it
+type yamlPath []string
+
+// child returns the path of a mapping value or sequence item below the path
+func (p yamlPath) child(segment string) yamlPath {
+	return append(p[:len(p):len(p)], segment)
+}
+
+// hasPrefix reports whether the path is at or below another path
+func (p yamlPath) hasPrefix(prefix yamlPath) bool {
+	return len(p) >= len(prefix) && slices.Equal(p[:len(prefix)], prefix)
+}
+
+// interpolate replaces references to environment variables and files in the scalars of a
+// YAML document, returning the scalars to redact from dumps: those with file contents or
+// variables not marked public. Offline, file references are left as they are. Errors
+// carry the file position of the reference.
+func interpolate(node *yaml.Node, file string, offline bool) ([]secretScalar, error) {
+	var secrets []secretScalar
+	// Scalars already interpolated, as an anchored scalar is reached again through its aliases
+	expanded := make(map[*yaml.Node]*secretScalar)
+
+	var walk func(node *yaml.Node, path yamlPath, key bool) error
+	walk = func(node *yaml.Node, path yamlPath, key bool) error {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				if err := walk(child, path, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				if err := walk(child, path.child(strconv.Itoa(i)), false); err != nil {
+					return err
+				}
+			}
+			return nil
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keyNode, value := node.Content[i], node.Content[i+1]
+				if err := walk(keyNode, path.child(keyNode.Value), true); err != nil {
+					return err
+				}
+				if keyNode.ShortTag() != mergeTag {
+					if err := walk(value, path.child(keyNode.Value), false); err != nil {
+						return err
+					}
+					continue
+				}
+				// Merged mappings are decoded into the mapping itself
+				merged := []*yaml.Node{value}
+				if value.Kind == yaml.SequenceNode {
+					merged = value.Content
+				}
+				for _, mapping := range merged {
+					if err := walk(mapping, path, false); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		case yaml.AliasNode:
+			return walk(node.Alias, path, key)
+		}
+
+		secret, seen := expanded[node]
+		if !seen {
+			if !strings.Contains(node.Value, "${") {
+				return nil
+			}
+			value, redacted, hasSecrets, err := expandReferences(node.Value, offline)
+			if err != nil {
+				return fmt.Errorf("%s:%d:%d: %w", file, node.Line, node.Column, err)
+			}
+			node.Value = value
+			// Plain scalars are resolved again, so interpolated numbers and booleans keep their type
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+			if hasSecrets {
+				secret = &secretScalar{value: value, redacted: redacted}
+			}
+			expanded[node] = secret
+		}
+		if secret != nil {
+			secrets = append(secrets, secretScalar{path: path, key: key, value: secret.value, redacted: secret.redacted})
+		}
+		return nil
+	}
+
+	if err := walk(node, nil, false); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// expandReferences replaces the references in a scalar value. It also returns the value with
+// the secret references redacted, and whether there are any.
+func expandReferences(value string, offline bool) (string, string, bool, error) {
+	var expanded, redacted strings.Builder
+	var hasSecrets bool
+	last := 0
+	for _, match := range interpolationPattern.FindAllStringIndex(value, -1) {
+		expanded.WriteString(value[last:match[0]])
+		redacted.WriteString(value[last:match[0]])
+		last = match[1]
+
+		content, secret, err := expandReference(value[match[0]:match[1]], offline)
+		if err != nil {
+			return "", "", false, err
+		}
+		expanded.WriteString(content)
+		if secret {
+			redacted.WriteString(redactedSecret)
+			hasSecrets = true
+		} else {
+			redacted.WriteString(content)
+		}
+	}
+	expanded.WriteString(value[last:])
+	redacted.WriteString(value[last:])
+	return expanded.String(), redacted.String(), hasSecrets, nil
+}
+
+// expandReference returns the value of a reference, and whether it is secret: file contents,
+// and environment variable values unless the reference is marked public. Defaults are part
+// of the file, so they are not secret.
+func expandReference(reference string, offline bool) (string, bool, error) {
+	if reference == "$${" {
+		return "${", false, nil
+	}
+	body := reference[2 : len(reference)-1]
+
+	if path, isFile := strings.CutPrefix(body, "file:"); isFile {
+		if offline {
+			return reference, false, nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // Secret files are provided by the operator
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s: %w", reference, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	}
+
+	body, public := strings.CutPrefix(body, publicPrefix)
+	match := envReferencePattern.FindStringSubmatch(body)
+	if match == nil {
+		return "", false, fmt.Errorf("invalid reference %s", reference)
+	}
+	name, hasDefault := match[1], strings.Contains(body, ":-")
+	content, set := os.LookupEnv(name)
+	switch {
+	case hasDefault && content == "":
+		return match[2], false, nil
+	case !set:
+		return "", false, fmt.Errorf("environment variable %s is not set", name)
+	}
+	return content, !public, nil
+}
+
+// afterEndpoints returns the scalar located for a file whose endpoints are appended to others
+func (s secretScalar) afterEndpoints(offset int) secretScalar {
+	if offset == 0 || len(s.path) < 2 || s.path[0] != endpointsKey {
+		return s
+	}
+	i, err := strconv.Atoi(s.path[1])
+	if err != nil {
+		return s
+	}
+	path := slices.Clone(s.path)
+	path[1] = strconv.Itoa(i + offset)
+	s.path = path
+	return s
+}
+
+// copySecrets redacts the copy of a setting made at another location, e.g. a template field
+// filled into a backend
+func (c *Config) copySecrets(from, to yamlPath) {
+	for _, secret := range c.secrets {
+		if !secret.path.hasPrefix(from) {
+			continue
+		}
+		secret.path = append(slices.Clone(to), secret.path[len(from):]...)
+		c.secrets = append(c.secrets, secret)
+	}
+}
+
+// redact replaces the scalar in an encoded configuration, if it is still there. Its value is
+// redacted as a whole if decoding changed it.
+func (s secretScalar) redact(root *yaml.Node) {
+	node := root
+	for i, segment := range s.path {
+		node = yamlChild(node, segment, s.key && i == len(s.path)-1)
+		if node == nil {
+			return
+		}
+	}
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+	if node.Value == s.value {
+		node.Value = s.redacted
+	} else {
+		node.Value = redactedSecret
+	}
+	// A redacted number or boolean is no longer one
+	node.Tag = "!!str"
+}
+
+// yamlChild returns the value or, for a key, the key node of a mapping entry, or a sequence item
+func yamlChild(node *yaml.Node, segment string, key bool) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				if key {
+					return node.Content[i]
+				}
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// Dump encodes the configuration as YAML, redacting inline secrets and
+// interpolated values that came from files or environment variables not marked public
+func (c *Config) Dump() ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(c); err != nil {
+		return nil, err
+	}
+	for _, secret := range c.secrets {
+		secret.redact(&root)
+	}
+	return yaml.Marshal(&root)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadConfig_Interpolation(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "client-secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600))
+
+	t.Setenv("USERS_HOST", "http://users.staging")
+	t.Setenv("EMPTY_TIMEOUT", "")
+	t.Setenv("METRICS", "true")
+	t.Setenv("API_TOKEN", "tok-123")
+	t.Setenv("DB_PASS", "hunter2")
+
+	configYAML := `
+metrics_enabled: ${public:METRICS}
+port: "${PORT_UNSET:-9090}"
+endpoints:
+  - endpoint: "/users/{user}"
+    timeout: ${EMPTY_TIMEOUT:-3s}
+    backends:
+      - host: ${public:USERS_HOST}
+        url_pattern: "/v1/users/{user}"
+        set_headers:
+          X-Token: "Bearer ${API_TOKEN}"
+          X-Database: "${DB_PASS}"
+          X-Literal: "$${NOT_INTERPOLATED}"
+          X-User: "{path.user}"
+        auth:
+          type: oauth2
+          token_url: "${USERS_HOST}/oauth/token"
+          client_id: aggregator
+          client_secret:
+            value: ${file:` + secretFile + `}
+`
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	assert.True(t, cfg.MetricsEnabled)
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, 3*time.Second, cfg.Endpoints[0].Timeout)
+
+	backend := cfg.Endpoints[0].Backends[0]
+	assert.Equal(t, "http://users.staging", backend.Host)
+	assert.Equal(t, "Bearer tok-123", backend.SetHeaders["X-Token"])
+	assert.Equal(t, "${NOT_INTERPOLATED}", backend.SetHeaders["X-Literal"])
+	assert.Equal(t, "{path.user}", backend.SetHeaders["X-User"])
+	assert.Equal(t, "s3cr3t", backend.Auth.ClientSecret.Value)
+
+	dump, err := cfg.Dump()
+	require.NoError(t, err)
+	assert.NotContains(t, string(dump), "s3cr3t")
+	assert.NotContains(t, string(dump), "tok-123")
+	assert.Contains(t, string(dump), "X-Token: Bearer REDACTED")
+	assert.NotContains(t, string(dump), "hunter2", "values are redacted whatever the variable name")
+	assert.Contains(t, string(dump), "X-Database: REDACTED")
+	assert.Contains(t, string(dump), "token_url: REDACTED/oauth/token")
+	assert.Contains(t, string(dump), "host: http://users.staging", "public values are kept")
+	assert.Contains(t, string(dump), "metrics_enabled: true")
+	assert.Contains(t, string(dump), `port: "9090"`, "defaults are kept")
+}
+
+func TestConfig_DumpRedactsSecretScalars(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("users-key\n"), 0o600))
+
+	// Short secrets also appear in unrelated values
+	t.Setenv("USERS_TOKEN", "1")
+	t.Setenv("ORDERS_SECRET_TIMEOUT", "1500ms")
+
+	mainYAML := `
+backend_templates:
+  users-service:
+    host: "http://users"
+    set_headers:
+      X-API-Key: ${file:` + keyFile + `}
+endpoints:
+  - endpoint: "/v1/users/{user}"
+    backends:
+      - use: users-service
+        url_pattern: "/v1/users/{user}"
+        set_headers: &headers
+          X-Token: "Bearer ${USERS_TOKEN}"
+          X-Version: "1"
+      - host: "http://users"
+        url_pattern: "/v1/users/{user}/settings"
+        group: settings
+        set_headers: *headers
+      - use: users-service
+        url_pattern: "/v1/users/{user}/keys"
+        group: keys
+`
+	ordersYAML := `
+endpoints:
+  - endpoint: "/v1/orders"
+    backends:
+      - host: "http://orders"
+        timeout: ${ORDERS_SECRET_TIMEOUT}
+        set_headers:
+          X-Token: ${USERS_TOKEN}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(mainYAML), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(ordersYAML), 0o600))
+
+	cfg, err := LoadConfig(dir)
+	require.NoError(t, err)
+	dumped, err := cfg.Dump()
+	require.NoError(t, err)
+
+	type dumpedBackend struct {
+		URLPattern string            `yaml:"url_pattern"`
+		SetHeaders map[string]string `yaml:"set_headers"`
+	}
+	var dump struct {
+		BackendTemplates map[string]dumpedBackend `yaml:"backend_templates"`
+		Endpoints        []struct {
+			Endpoint string          `yaml:"endpoint"`
+			Backends []dumpedBackend `yaml:"backends"`
+		} `yaml:"endpoints"`
+	}
+	require.NoError(t, yaml.Unmarshal(dumped, &dump))
+	assert.NotContains(t, string(dumped), "users-key")
+
+	// Only the interpolated scalars are redacted, keeping their other parts
+	users := dump.Endpoints[0].Backends[0]
+	assert.Equal(t, "/v1/users/{user}", dump.Endpoints[0].Endpoint)
+	assert.Equal(t, "/v1/users/{user}", users.URLPattern)
+	assert.Equal(t, "Bearer REDACTED", users.SetHeaders["X-Token"])
+	assert.Equal(t, "1", users.SetHeaders["X-Version"])
+
+	// Secrets are redacted where they are copied: through aliases and from templates
+	assert.Equal(t, "REDACTED", dump.BackendTemplates["users-service"].SetHeaders["X-API-Key"])
+	settings := dump.Endpoints[0].Backends[1]
+	assert.Equal(t, "Bearer REDACTED", settings.SetHeaders["X-Token"])
+	assert.Equal(t, "1", settings.SetHeaders["X-Version"])
+	assert.Equal(t, "REDACTED", dump.Endpoints[0].Backends[2].SetHeaders["X-API-Key"])
+
+	// Secrets of later files are located after the endpoints of earlier files
+	require.Len(t, dump.Endpoints, 2)
+	orders := dump.Endpoints[1].Backends[0]
+	assert.Equal(t, "REDACTED", orders.SetHeaders["X-Token"])
+	assert.Contains(t, string(dumped), "timeout: REDACTED", "values changed by decoding are redacted as a whole")
+}
+
+func TestLoadConfig_InterpolationErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		configYAML string
+		errorMsg   string
+	}{
+		{
+			name: "missing required variable",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: ${UNSET_USERS_HOST}
+`,
+			errorMsg: "config.yaml:5:15: environment variable UNSET_USERS_HOST is not set",
+		},
+		{
+			name: "missing file",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users"
+        auth:
+          type: bearer
+          token:
+            value: ${file:/nonexistent/token}
+`,
+			errorMsg: "config.yaml:9:20: failed to read ${file:/nonexistent/token}",
+		},
+		{
+			name: "invalid reference",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "${users host}"
+`,
+			errorMsg: "config.yaml:5:15: invalid reference ${users host}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(tt.configYAML), 0o600))
+
+			_, err := LoadConfig(configFile)
+			require.Error(t, err)
+			assert.Contains(t, strings.ReplaceAll(err.Error(), configFile, "config.yaml"), tt.errorMsg)
+		})
+	}
+}
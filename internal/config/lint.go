@@ -0,0 +1,208 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Lint issue severities
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// urlParamPattern matches the path parameters of a URL or route pattern
+var urlParamPattern = regexp.MustCompile(`\{([^{}:]+)(?::[^{}]+)?\}`)
+
+// LintIssue is a risky setup that validation accepts
+type LintIssue struct {
+	// LintError or LintWarning
+	Severity string
+
+	// Where the issue is, e.g. "endpoint GET /users, backend 1"; empty for the file as a whole
+	Location string
+
+	Message string
+}
+
+// String formats the issue for display
+func (i LintIssue) String() string {
+	if i.Location == "" {
+		return i.Severity + ": " + i.Message
+	}
+	return i.Severity + ": " + i.Location + ": " + i.Message
+}
+
+// HasLintErrors reports whether any of the issues is an error
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks a validated configuration for setups that are accepted but likely mistakes:
+// duplicate and overlapping routes, URL pattern parameters missing from the endpoint path,
+// backends merged into the same fields, ignored deny lists, colliding mappings, backend
+// timeouts exceeding the endpoint timeout and unknown YAML keys.
+func (c *Config) Lint() []LintIssue {
+	var issues []LintIssue
+
+	for _, field := range c.unknownFields {
+		issues = append(issues, LintIssue{Severity: LintWarning, Message: field})
+	}
+
+	issues = append(issues, c.lintRoutes()...)
+	for _, endpoint := range c.Endpoints {
+		issues = append(issues, lintEndpoint(endpoint)...)
+	}
+	return issues
+}
+
+// lintRoutes reports endpoints registered twice, and endpoints matching the same requests
+func (c *Config) lintRoutes() []LintIssue {
+	var issues []LintIssue
+	for i, a := range c.Endpoints {
+		for _, b := range c.Endpoints[i+1:] {
+			if !strings.EqualFold(a.Method, b.Method) {
+				continue
+			}
+			location := "endpoint " + routeName(b)
+			switch {
+			case a.Endpoint == b.Endpoint:
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Location: location,
+					Message:  "duplicate endpoint, only one of them is served",
+				})
+			case routesOverlap(a.Endpoint, b.Endpoint):
+				issues = append(issues, LintIssue{
+					Severity: LintWarning,
+					Location: location,
+					Message:  "overlaps with endpoint " + routeName(a) + ", static segments take precedence",
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// routesOverlap reports whether two route patterns can match the same path
+func routesOverlap(a, b string) bool {
+	aSegments, bSegments := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(aSegments) != len(bSegments) {
+		return false
+	}
+	for i := range aSegments {
+		if aSegments[i] != bSegments[i] && !isRouteParam(aSegments[i]) && !isRouteParam(bSegments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRouteParam reports whether a route segment is a single path parameter
+func isRouteParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// routeName names an endpoint by method and path
+func routeName(endpoint Endpoint) string {
+	return strings.ToUpper(endpoint.Method) + " " + endpoint.Endpoint
+}
+
+// lintEndpoint checks the backends of an endpoint
+func lintEndpoint(endpoint Endpoint) []LintIssue {
+	var issues []LintIssue
+	endpointLocation := "endpoint " + routeName(endpoint)
+
+	var merged []string
+	for j, backend := range endpoint.Backends {
+		location := fmt.Sprintf("%s, backend %d", endpointLocation, j)
+		add := func(severity, message string) {
+			issues = append(issues, LintIssue{Severity: severity, Location: location, Message: message})
+		}
+
+		patterns := []string{backend.URLPattern}
+		for _, fallback := range backend.Fallback {
+			patterns = append(patterns, fallback.URLPattern)
+		}
+		for _, pattern := range patterns {
+			for _, match := range urlParamPattern.FindAllStringSubmatch(pattern, -1) {
+				if !hasPathParam(endpoint.Endpoint, match[1]) {
+					add(LintError, fmt.Sprintf("url_pattern %s: parameter %s is not in the endpoint path and is sent as is",
+						pattern, match[1]))
+				}
+			}
+		}
+
+		if backend.Group == "" && backend.Concat == "" && backend.Join == nil {
+			merged = append(merged, fmt.Sprint(j))
+		}
+
+		if len(backend.Allow) > 0 && len(backend.Deny) > 0 {
+			add(LintWarning, "allow and deny are both set, deny is ignored")
+		}
+		if backend.Body != nil && len(backend.Body.Allow) > 0 && len(backend.Body.Deny) > 0 {
+			add(LintWarning, "body: allow and deny are both set, deny is ignored")
+		}
+
+		for _, message := range mappingCollisions(backend.Mapping) {
+			add(LintWarning, "mapping: "+message)
+		}
+		if backend.Body != nil {
+			for _, message := range mappingCollisions(backend.Body.Mapping) {
+				add(LintWarning, "body mapping: "+message)
+			}
+		}
+
+		if endpoint.Timeout > 0 && backend.Timeout > endpoint.Timeout {
+			add(LintWarning, fmt.Sprintf("timeout %s exceeds the endpoint timeout %s", backend.Timeout, endpoint.Timeout))
+		}
+	}
+
+	if len(merged) > 1 {
+		issues = append(issues, LintIssue{
+			Severity: LintWarning,
+			Location: endpointLocation,
+			Message: fmt.Sprintf("backends %s have neither group nor concat, their responses are merged "+
+				"into the same object and colliding fields follow the %s merge strategy",
+				strings.Join(merged, ", "), mergeStrategyName(endpoint)),
+		})
+	}
+
+	return issues
+}
+
+// mappingCollisions describes the fields of a mapping renamed to the same name
+func mappingCollisions(mapping map[string]string) []string {
+	sources := make(map[string][]string)
+	for from, to := range mapping {
+		sources[to] = append(sources[to], from)
+	}
+
+	var messages []string
+	for to, from := range sources {
+		if len(from) > 1 {
+			sort.Strings(from)
+			messages = append(messages, fmt.Sprintf("%s are all mapped to %s", strings.Join(from, ", "), to))
+		}
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// mergeStrategyName returns the merge strategy of an endpoint
+func mergeStrategyName(endpoint Endpoint) string {
+	if endpoint.Merge.Strategy != "" {
+		return endpoint.Merge.Strategy
+	}
+	return defaultMergeStrategy
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name       string
+		configYAML string
+		expected   []string
+	}{
+		{
+			name: "clean configuration",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{user}"
+    backends:
+      - host: "http://users"
+        url_pattern: "/users/{user}"
+        group: user
+      - host: "http://posts"
+        url_pattern: "/posts"
+        concat: posts
+`,
+		},
+		{
+			name: "duplicate and overlapping endpoints",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{user}"
+    backends:
+      - host: "http://users"
+  - endpoint: "/users/{user}"
+    backends:
+      - host: "http://users"
+  - endpoint: "/users/me"
+    backends:
+      - host: "http://users"
+  - endpoint: "/users/me"
+    method: POST
+    backends:
+      - host: "http://users"
+`,
+			expected: []string{
+				"error: endpoint GET /users/{user}: duplicate endpoint, only one of them is served",
+				"warning: endpoint GET /users/me: overlaps with endpoint GET /users/{user}, static segments take precedence",
+				"warning: endpoint GET /users/me: overlaps with endpoint GET /users/{user}, static segments take precedence",
+			},
+		},
+		{
+			name: "url pattern parameter missing from endpoint path",
+			configYAML: `
+endpoints:
+  - endpoint: "/users/{user}"
+    backends:
+      - host: "http://users"
+        url_pattern: "/users/{id}"
+        timeout: 2s
+        fallback:
+          - host: "http://users-backup"
+            url_pattern: "/v2/users/{user}"
+`,
+			expected: []string{
+				"error: endpoint GET /users/{user}, backend 0: url_pattern /users/{id}: " +
+					"parameter id is not in the endpoint path and is sent as is",
+			},
+		},
+		{
+			name: "backends merged into the same object",
+			configYAML: `
+endpoints:
+  - endpoint: "/dashboard"
+    merge:
+      strategy: first_wins
+    backends:
+      - host: "http://users"
+      - host: "http://stats"
+        group: stats
+      - host: "http://posts"
+`,
+			expected: []string{
+				"warning: endpoint GET /dashboard: backends 0, 2 have neither group nor concat, " +
+					"their responses are merged into the same object and colliding fields follow the first_wins merge strategy",
+			},
+		},
+		{
+			name: "allow and deny, colliding mappings and timeouts",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    method: POST
+    timeout: 1s
+    backends:
+      - host: "http://users"
+        timeout: 2s
+        allow: [id, name]
+        deny: [email]
+        mapping:
+          name: label
+          title: label
+        body:
+          allow: [id]
+          deny: [secret]
+          mapping:
+            a: c
+            b: c
+`,
+			expected: []string{
+				"warning: endpoint POST /users, backend 0: allow and deny are both set, deny is ignored",
+				"warning: endpoint POST /users, backend 0: body: allow and deny are both set, deny is ignored",
+				"warning: endpoint POST /users, backend 0: mapping: name, title are all mapped to label",
+				"warning: endpoint POST /users, backend 0: body mapping: a, b are all mapped to c",
+				"warning: endpoint POST /users, backend 0: timeout 2s exceeds the endpoint timeout 1s",
+			},
+		},
+		{
+			name: "unknown YAML keys",
+			configYAML: `
+allow_unknown_fields: true
+endpoints:
+  - endpoint: "/users"
+    backend:
+      - host: "http://other"
+    backends:
+      - host: "http://users"
+        url_patern: "/users"
+`,
+			expected: []string{
+				`warning: config.yaml:5:5: unknown field "backend" in endpoints[0], did you mean "backends"?`,
+				`warning: config.yaml:9:9: unknown field "url_patern" in endpoints[0].backends[0], did you mean "url_pattern"?`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(tt.configYAML), 0o600))
+
+			cfg, err := LoadConfig(configFile)
+			require.NoError(t, err)
+
+			var issues []string
+			for _, issue := range cfg.Lint() {
+				issues = append(issues, strings.ReplaceAll(issue.String(), configFile, "config.yaml"))
+			}
+			assert.Equal(t, tt.expected, issues)
+		})
+	}
+}
+
+func TestRoutesOverlap(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "/users/{id}", b: "/users/me", expected: true},
+		{a: "/users/{id}", b: "/users/{name:[a-z]+}", expected: true},
+		{a: "/users/{id}", b: "/posts/{id}", expected: false},
+		{a: "/users/{id}", b: "/users/{id}/posts", expected: false},
+		{a: "/users", b: "/posts", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, routesOverlap(tt.a, tt.b))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// readmeYAMLBlock matches the YAML code blocks of the README
+var readmeYAMLBlock = regexp.MustCompile("(?s)```yaml\n(.*?)```")
+
+// TestReadmeExamples checks that every YAML example in the README decodes without unknown
+// keys, as a whole configuration, an endpoint, a backend or a list of either
+func TestReadmeExamples(t *testing.T) {
+	readme, err := os.ReadFile("../../README.md")
+	require.NoError(t, err)
+
+	blocks := readmeYAMLBlock.FindAllSubmatch(readme, -1)
+	require.NotEmpty(t, blocks)
+
+	for _, block := range blocks {
+		example := string(block[1])
+		name := strings.SplitN(strings.TrimSpace(example), "\n", 2)[0]
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("USERS_HOST", "http://users")
+			t.Setenv("METRICS", "true")
+
+			var root yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(example), &root))
+			// Examples reference files that only exist where the service is deployed
+			_, err := interpolate(&root, "README.md", true)
+			require.NoError(t, err)
+
+			candidates := []reflect.Type{
+				reflect.TypeOf(Config{}),
+				reflect.TypeOf(Endpoint{}),
+				reflect.TypeOf(Backend{}),
+			}
+			if root.Content[0].Kind == yaml.SequenceNode {
+				candidates = []reflect.Type{
+					reflect.TypeOf([]Endpoint{}),
+					reflect.TypeOf([]Backend{}),
+				}
+			}
+
+			var unknown []string
+			for _, candidate := range candidates {
+				fields := findUnknownFields(&root, candidate)
+				if len(fields) > 0 {
+					for _, field := range fields {
+						unknown = append(unknown, candidate.String()+": "+field.format("README.md"))
+					}
+					continue
+				}
+				value := reflect.New(candidate)
+				assert.NoError(t, root.Decode(value.Interface()))
+				return
+			}
+			t.Errorf("example matches no configuration type:\n%s", strings.Join(unknown, "\n"))
+		})
+	}
+}
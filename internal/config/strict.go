@@ -0,0 +1,189 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownField is a YAML key that matches no configuration field
+type unknownField struct {
+	Line   int
+	Column int
+
+	// Key name and the location of its mapping, e.g. endpoints[0].backends[1]
+	Key  string
+	Path string
+
+	// Closest known key, if any is close enough to be a likely typo
+	Suggestion string
+}
+
+// format describes the unknown field, prefixed with the file position
+func (f unknownField) format(file string) string {
+	message := fmt.Sprintf("%s:%d:%d: unknown field %q", file, f.Line, f.Column, f.Key)
+	if f.Path != "" {
+		message += " in " + f.Path
+	}
+	if f.Suggestion != "" {
+		message += fmt.Sprintf(", did you mean %q?", f.Suggestion)
+	}
+	return message
+}
+
+// findUnknownFields returns the keys of a YAML document that match no field of the type, in document order
+func findUnknownFields(root *yaml.Node, t reflect.Type) []unknownField {
+	var fields []unknownField
+	walkKnownFields(root, t, "", &fields)
+	return fields
+}
+
+// walkKnownFields checks the keys of a node against the fields of the type it decodes into
+func walkKnownFields(node *yaml.Node, t reflect.Type, path string, fields *[]unknownField) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types decoding themselves accept any keys
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()) {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkKnownFields(child, t, path, fields)
+		}
+
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, child := range node.Content {
+			walkKnownFields(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walkKnownFields(node.Content[i+1], t.Elem(), joinFieldPath(path, node.Content[i].Value), fields)
+			}
+		case reflect.Struct:
+			known := yamlFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				// Merge keys add the keys of another mapping to this one
+				if key.Tag == "!!merge" || key.Value == "<<" {
+					walkKnownFields(value, t, path, fields)
+					continue
+				}
+
+				// Extension keys hold anchors for reuse elsewhere in the file
+				if strings.HasPrefix(key.Value, "x-") {
+					continue
+				}
+
+				fieldType, ok := known[key.Value]
+				if !ok {
+					*fields = append(*fields, unknownField{
+						Line:       key.Line,
+						Column:     key.Column,
+						Key:        key.Value,
+						Path:       path,
+						Suggestion: suggestField(key.Value, known),
+					})
+					continue
+				}
+				walkKnownFields(value, fieldType, joinFieldPath(path, key.Value), fields)
+			}
+		default:
+		}
+
+	default:
+	}
+}
+
+// yamlFields returns the YAML keys of a struct with their types, including inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if strings.Contains(options, "inline") {
+			for key, fieldType := range yamlFields(field.Type) {
+				fields[key] = fieldType
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggestField returns the known key closest to an unknown one, if the difference is small enough to be a typo
+func suggestField(key string, known map[string]reflect.Type) string {
+	normalized := strings.ReplaceAll(strings.ToLower(key), "-", "_")
+
+	best, bestDistance := "", 0
+	for name := range known {
+		distance := editDistance(normalized, name)
+		if best == "" || distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+
+	// Allow about one edit per four characters, and at least two
+	if best == "" || bestDistance > max(2, len(best)/4) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// joinFieldPath appends a key to a field path
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
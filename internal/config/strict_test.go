@@ -0,0 +1,130 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		configYAML string
+		errorMsg   string
+	}{
+		{
+			name: "known fields only",
+			configYAML: `
+x-defaults: &defaults
+  host: "http://users"
+  timeout: 1s
+endpoints:
+  - endpoint: "/users"
+    merge:
+      strategy: first_wins
+      record_conflicts: true
+    response_schema:
+      anything: goes
+    backends:
+      - <<: *defaults
+        url_pattern: "/users"
+host_transports:
+  "http://users":
+    max_idle_conns: 10
+allow_unknown_fields: false
+`,
+		},
+		{
+			name: "typo with suggestion",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users"
+        url_patern: "/users"
+`,
+			errorMsg: `config.yaml:6:9: unknown field "url_patern" in endpoints[0].backends[0], did you mean "url_pattern"?`,
+		},
+		{
+			name: "typo in map value and inlined struct",
+			configYAML: `
+host_transports:
+  "http://users":
+    max_idle_con: 10
+endpoints:
+  - endpoint: "/users"
+    merge:
+      stratgy: first_wins
+    backends:
+      - host: "http://users"
+`,
+			errorMsg: `config.yaml:4:5: unknown field "max_idle_con" in host_transports.http://users, ` +
+				`did you mean "max_idle_conns"?; ` +
+				`config.yaml:8:7: unknown field "stratgy" in endpoints[0].merge, did you mean "strategy"?`,
+		},
+		{
+			name: "unrelated key without suggestion",
+			configYAML: `
+colour: blue
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users"
+`,
+			errorMsg: `config.yaml:2:1: unknown field "colour"`,
+		},
+		{
+			name: "unknown fields allowed",
+			configYAML: `
+allow_unknown_fields: true
+future_setting: true
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - host: "http://users"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(tt.configYAML), 0o600))
+
+			_, err := LoadConfig(configFile)
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, "failed to parse config file: "+tt.errorMsg,
+				strings.ReplaceAll(err.Error(), configFile, "config.yaml"))
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "abc", expected: 3},
+		{a: "backend", b: "backends", expected: 1},
+		{a: "url_patern", b: "url_pattern", expected: 1},
+		{a: "timeuot", b: "timeout", expected: 2},
+		{a: "same", b: "same", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, editDistance(tt.a, tt.b))
+		})
+	}
+}
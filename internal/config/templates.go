@@ -0,0 +1,229 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Upstream represents a backend service shared by several backends
+type Upstream struct {
+	// Host of the service
+	Host string `yaml:"host"`
+
+	// TLS settings for connections to the service - optional
+	TLS *TLS `yaml:"tls,omitempty"`
+
+	// Outbound authentication for the service - optional
+	Auth *Auth `yaml:"auth,omitempty"`
+
+	// HTTP transport settings for the service host (overrides the defaults field by field) - optional
+	Transport *Transport `yaml:"transport,omitempty"`
+}
+
+// applyBackendTemplate fills the fields a backend leaves unset from the template or upstream it
+// uses. A template is applied first, then the upstream the template uses, so fields set on the
+// backend override the template, which overrides the upstream. Unknown names are left to validate.
+// Interpolated secrets copied to the backend at path are redacted there as well.
+func (c *Config) applyBackendTemplate(backend *Backend, path yamlPath) {
+	upstreamName := backend.Use
+	if template, ok := c.BackendTemplates[backend.Use]; ok {
+		filled := fillUnsetFields(backend, template)
+		for _, key := range filled {
+			c.copySecrets(yamlPath{backendTemplatesKey, backend.Use, key}, path.child(key))
+		}
+		upstreamName = template.Use
+	}
+
+	upstream, ok := c.Upstreams[upstreamName]
+	if !ok {
+		return
+	}
+	var filled []string
+	if backend.Host == "" {
+		backend.Host = upstream.Host
+		filled = append(filled, "host")
+	}
+	if backend.TLS == nil && upstream.TLS != nil {
+		backend.TLS = deepCopy(reflect.ValueOf(upstream.TLS)).Interface().(*TLS)
+		filled = append(filled, "tls")
+	}
+	if backend.Auth == nil && upstream.Auth != nil {
+		backend.Auth = deepCopy(reflect.ValueOf(upstream.Auth)).Interface().(*Auth)
+		filled = append(filled, "auth")
+	}
+	for _, key := range filled {
+		c.copySecrets(yamlPath{upstreamsKey, upstreamName, key}, path.child(key))
+	}
+}
+
+// fillUnsetFields sets the fields a backend leaves unset to copies of the fields the template
+// sets, returning their YAML keys. A template's name and use are its own.
+func fillUnsetFields(backend *Backend, template Backend) []string {
+	dst, src := reflect.ValueOf(backend).Elem(), reflect.ValueOf(template)
+	var filled []string
+	for i := 0; i < dst.NumField(); i++ {
+		key := yamlFieldName(dst.Type().Field(i))
+		field := dst.Field(i)
+		if !field.CanSet() || key == "name" || key == "use" {
+			continue
+		}
+		if !backend.isSet(key, field) && template.isSet(key, src.Field(i)) {
+			field.Set(deepCopy(src.Field(i)))
+			filled = append(filled, key)
+		}
+	}
+	return filled
+}
+
+// isSet reports whether the backend sets the field with a YAML key: in the configuration
+// file, or to a non-zero value for a backend built in code
+func (b Backend) isSet(key string, field reflect.Value) bool {
+	if b.setFields == nil {
+		return !field.IsZero()
+	}
+	return b.setFields[key]
+}
+
+// deepCopy copies a value, including the maps, slices and pointers it holds,
+// so backends using the same template never share mutable state
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopy(v.Elem()))
+		return copied
+	default:
+		return v
+	}
+}
+
+// upstreamTransport returns the transport settings of the upstream serving a host
+func (c *Config) upstreamTransport(host string) (Transport, bool) {
+	for _, name := range sortedNames(c.Upstreams) {
+		upstream := c.Upstreams[name]
+		if upstream.Transport != nil && sameHost(upstream.Host, host) {
+			return *upstream.Transport, true
+		}
+	}
+	return Transport{}, false
+}
+
+// sameHost reports whether two hosts are the same, ignoring a trailing slash
+func sameHost(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// validateTemplates checks the upstreams and backend templates. The backends using them are
+// validated with the template fields applied.
+func (c *Config) validateTemplates() error {
+	// Transports apply by host, so one upstream at most may set the transport of a host
+	transportHosts := make(map[string]string)
+	for _, name := range sortedNames(c.Upstreams) {
+		if err := c.validateUpstream(name, transportHosts); err != nil {
+			return c.inFile(upstreamsKey+" "+name, err)
+		}
+	}
+
+	for _, name := range sortedNames(c.BackendTemplates) {
+		template := c.BackendTemplates[name]
+		if template.Use == "" {
+			continue
+		}
+		if _, ok := c.Upstreams[template.Use]; !ok {
+			return c.inFile(backendTemplatesKey+" "+name,
+				fmt.Errorf("backend template %s: unknown upstream %s", name, template.Use))
+		}
+	}
+	return nil
+}
+
+// validateUpstream checks an upstream, recording the host whose transport it sets
+func (c *Config) validateUpstream(name string, transportHosts map[string]string) error {
+	upstream := c.Upstreams[name]
+	if _, ok := c.BackendTemplates[name]; ok {
+		return fmt.Errorf("upstream %s: a backend template has the same name", name)
+	}
+	if upstream.Host == "" {
+		return fmt.Errorf("upstream %s: host is required", name)
+	}
+	if upstream.Transport != nil {
+		host := strings.TrimSuffix(upstream.Host, "/")
+		if other, ok := transportHosts[host]; ok {
+			return fmt.Errorf("upstream %s: transport for host %s is also set by upstream %s", name, upstream.Host, other)
+		}
+		transportHosts[host] = name
+		if _, ok := c.hostTransportSetting(upstream.Host); ok {
+			return fmt.Errorf("upstream %s: transport for host %s is also set in host_transports", name, upstream.Host)
+		}
+		if err := c.validateTransport("upstream "+name, *upstream.Transport); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBackendUse checks that a backend uses a known template or upstream
+func (c *Config) validateBackendUse(endpointName string, j int, backend Backend) error {
+	if backend.Use == "" {
+		return nil
+	}
+	_, isTemplate := c.BackendTemplates[backend.Use]
+	_, isUpstream := c.Upstreams[backend.Use]
+	if !isTemplate && !isUpstream {
+		return fmt.Errorf("endpoint %s, backend %d: unknown backend template or upstream %s", endpointName, j, backend.Use)
+	}
+	return nil
+}
+
+// sortedNames returns the keys of a map in sorted order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_BackendTemplates(t *testing.T) {
+	configYAML := `
+transport:
+  max_idle_conns: 100
+upstreams:
+  users-api:
+    host: "https://users.internal"
+    tls:
+      server_name: users.internal
+    auth:
+      type: bearer
+      token:
+        value: users-token
+    transport:
+      max_idle_conns: 10
+backend_templates:
+  users-service:
+    use: users-api
+    encoding: json
+    timeout: 2s
+    remove_headers: ["Cookie"]
+    rename_headers:
+      X-Request-Id: X-Correlation-Id
+endpoints:
+  - endpoint: "/users/{id}"
+    timeout: 5s
+    backends:
+      - use: users-service
+        url_pattern: "/v2/users/{id}"
+  - endpoint: "/users/{id}/profile"
+    timeout: 5s
+    backends:
+      - use: users-service
+        timeout: 4s
+        remove_headers: ["Authorization"]
+        auth:
+          type: api_key
+          token:
+            value: profile-key
+  - endpoint: "/status"
+    backends:
+      - use: users-api
+        url_pattern: "/status"
+`
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	// Unset fields come from the template, and the template's unset fields from the upstream
+	user := cfg.Endpoints[0].Backends[0]
+	assert.Equal(t, "https://users.internal", user.Host)
+	assert.Equal(t, "/v2/users/{id}", user.URLPattern)
+	assert.Equal(t, "json", user.Encoding)
+	assert.Equal(t, 2*time.Second, user.Timeout)
+	assert.Equal(t, []string{"Cookie"}, user.RemoveHeaders)
+	assert.Equal(t, map[string]string{"X-Request-Id": "X-Correlation-Id"}, user.RenameHeaders)
+	require.NotNil(t, user.TLS)
+	assert.Equal(t, "users.internal", user.TLS.ServerName)
+	require.NotNil(t, user.Auth)
+	assert.Equal(t, "bearer", user.Auth.Type)
+	assert.Equal(t, "users-token", user.Auth.Token.Value)
+
+	// Fields set on the backend override the template and the upstream
+	profile := cfg.Endpoints[1].Backends[0]
+	assert.Equal(t, "/users/{id}/profile", profile.URLPattern)
+	assert.Equal(t, 4*time.Second, profile.Timeout)
+	assert.Equal(t, []string{"Authorization"}, profile.RemoveHeaders)
+	require.NotNil(t, profile.Auth)
+	assert.Equal(t, "api_key", profile.Auth.Type)
+	assert.Equal(t, "X-API-Key", profile.Auth.Header)
+
+	// Backends may use an upstream directly
+	status := cfg.Endpoints[2].Backends[0]
+	assert.Equal(t, "https://users.internal", status.Host)
+	assert.Equal(t, "/status", status.URLPattern)
+	assert.Equal(t, defaultEncoding, status.Encoding)
+	assert.Zero(t, status.Timeout)
+
+	// Backends get their own copies of the template's maps, slices and pointers
+	user.RenameHeaders["X-Trace"] = "X-Trace"
+	user.RemoveHeaders[0] = "Set-Cookie"
+	user.Auth.Type = "basic"
+	template := cfg.BackendTemplates["users-service"]
+	assert.Equal(t, map[string]string{"X-Request-Id": "X-Correlation-Id"}, template.RenameHeaders)
+	assert.Equal(t, []string{"Cookie"}, template.RemoveHeaders)
+	assert.Equal(t, "bearer", cfg.Upstreams["users-api"].Auth.Type)
+	assert.Equal(t, "bearer", status.Auth.Type)
+
+	// The upstream transport applies to its host
+	assert.Equal(t, 10, cfg.TransportFor(user).MaxIdleConns)
+	assert.Equal(t, 100, cfg.TransportFor(Backend{Host: "http://other"}).MaxIdleConns)
+}
+
+func TestLoadConfig_BackendTemplateOverrides(t *testing.T) {
+	configYAML := `
+backend_templates:
+  users-service:
+    name: users
+    host: "http://users"
+    timeout: 2s
+    optional: true
+    group: user
+endpoints:
+  - endpoint: "/users/{id}"
+    timeout: 5s
+    backends:
+      - use: users-service
+        name: profile
+        optional: false
+        timeout: 0s
+      - &settings
+        use: users-service
+        group: settings
+      - <<: *settings
+        optional: false
+        group: preferences
+`
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	// Zero values set on the backend override the template
+	profile := cfg.Endpoints[0].Backends[0]
+	assert.Equal(t, "profile", profile.Name)
+	assert.Equal(t, "users-service", profile.Use)
+	assert.False(t, profile.Optional)
+	assert.Zero(t, profile.Timeout)
+	assert.Equal(t, "user", profile.Group)
+
+	// The template's name is not copied into the backends using it
+	settings := cfg.Endpoints[0].Backends[1]
+	assert.Empty(t, settings.Name)
+	assert.True(t, settings.Optional)
+	assert.Equal(t, 2*time.Second, settings.Timeout)
+
+	// Keys of merged mappings count as set
+	preferences := cfg.Endpoints[0].Backends[2]
+	assert.Equal(t, "users-service", preferences.Use)
+	assert.False(t, preferences.Optional)
+	assert.Equal(t, "preferences", preferences.Group)
+	assert.Equal(t, 2*time.Second, preferences.Timeout)
+
+	// Backends built in code leave their zero fields unset
+	built := Backend{Use: "users-service", Group: "built"}
+	cfg.applyBackendTemplate(&built, nil)
+	assert.True(t, built.Optional)
+	assert.Equal(t, "built", built.Group)
+	assert.Empty(t, built.Name)
+}
+
+func TestLoadConfig_BackendTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		configYAML string
+		errorMsg   string
+	}{
+		{
+			name: "unknown template",
+			configYAML: `
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users-service
+`,
+			errorMsg: "endpoint /users, backend 0: unknown backend template or upstream users-service",
+		},
+		{
+			name: "template using an unknown upstream",
+			configYAML: `
+backend_templates:
+  users-service:
+    use: users-api
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users-service
+        host: "http://users"
+`,
+			errorMsg: "backend template users-service: unknown upstream users-api",
+		},
+		{
+			name: "template using another template",
+			configYAML: `
+backend_templates:
+  base:
+    host: "http://users"
+  users-service:
+    use: base
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users-service
+`,
+			errorMsg: "backend template users-service: unknown upstream base",
+		},
+		{
+			name: "upstream and template with the same name",
+			configYAML: `
+upstreams:
+  users:
+    host: "http://users"
+backend_templates:
+  users:
+    encoding: json
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users
+`,
+			errorMsg: "upstream users: a backend template has the same name",
+		},
+		{
+			name: "upstream without host",
+			configYAML: `
+upstreams:
+  users:
+    auth:
+      type: bearer
+      token:
+        value: token
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users
+`,
+			errorMsg: "upstream users: host is required",
+		},
+		{
+			name: "upstream transport also in host transports",
+			configYAML: `
+host_transports:
+  "http://users":
+    max_idle_conns: 10
+upstreams:
+  users:
+    host: "http://users"
+    transport:
+      max_idle_conns: 20
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users
+`,
+			errorMsg: "upstream users: transport for host http://users is also set in host_transports",
+		},
+		{
+			name: "upstream transports for the same host",
+			configYAML: `
+upstreams:
+  users:
+    host: "http://svc"
+    transport:
+      max_conns_per_host: 10
+  orders:
+    host: "http://svc/"
+    transport:
+      max_conns_per_host: 20
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users
+`,
+			errorMsg: "upstream users: transport for host http://svc is also set by upstream orders",
+		},
+		{
+			name: "invalid upstream transport",
+			configYAML: `
+upstreams:
+  users:
+    host: "http://users"
+    transport:
+      dial_timeout: -1s
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users
+`,
+			errorMsg: "upstream users: timeouts must not be negative",
+		},
+		{
+			name: "invalid template field",
+			configYAML: `
+backend_templates:
+  users-service:
+    host: "http://users"
+    encoding: csv
+endpoints:
+  - endpoint: "/users"
+    backends:
+      - use: users-service
+`,
+			errorMsg: "endpoint /users, backend 0: invalid encoding csv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(tt.configYAML), 0o600))
+
+			_, err := LoadConfig(configFile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package merger
+
+import (
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// joinSource holds a processed backend response waiting to be joined into the result
+type joinSource struct {
+	join    config.Join
+	backend string
+	data    interface{}
+}
+
+// applyJoin merges the items of data element-wise into the array at the join path, matched by key.
+// With a left join unmatched target items are kept; with an inner join they are dropped.
+func (m *Merger) applyJoin(state *mergeState, result map[string]interface{}, join config.Join, data interface{}) {
+	targets, ok := m.transformer.GetNestedField(result, join.Path).([]interface{})
+	if !ok {
+		return
+	}
+
+	// Index the joined items by key, keeping the first item for each key
+	index := make(map[string]map[string]interface{})
+	for _, item := range m.joinItems(data) {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, ok := m.transformer.ItemKey(itemMap, join.ForeignKey)
+		if !ok {
+			continue
+		}
+		if _, exists := index[key]; !exists {
+			index[key] = itemMap
+		}
+	}
+
+	joined := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		match := m.findJoinMatch(index, target, join.Key)
+		if match == nil {
+			if join.Type == "left" {
+				joined = append(joined, target)
+			}
+			continue
+		}
+
+		// Merge the matched item into a copy of the target item
+		targetMap := target.(map[string]interface{})
+		merged := make(map[string]interface{}, len(targetMap)+len(match))
+		for k, v := range targetMap {
+			merged[k] = v
+		}
+		for k, v := range match {
+			m.deepMerge(state, merged, join.Path, k, v)
+		}
+		joined = append(joined, merged)
+	}
+
+	m.transformer.SetNestedField(result, join.Path, joined)
+}
+
+// findJoinMatch returns the indexed item matching the target item's key, or nil
+func (m *Merger) findJoinMatch(
+	index map[string]map[string]interface{},
+	target interface{},
+	keyField string,
+) map[string]interface{} {
+	if _, ok := target.(map[string]interface{}); !ok {
+		return nil
+	}
+	key, ok := m.transformer.ItemKey(target, keyField)
+	if !ok {
+		return nil
+	}
+	return index[key]
+}
+
+// joinItems returns the items of a joined response, treating a single object as one item
+func (m *Merger) joinItems(data interface{}) []interface{} {
+	if items, ok := data.([]interface{}); ok {
+		return items
+	}
+	return []interface{}{data}
+}
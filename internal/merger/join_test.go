@@ -0,0 +1,107 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package merger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/types"
+)
+
+func TestMerger_Join(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	users := types.BackendResponse{
+		Backend: config.Backend{Group: "users"},
+		Data: []interface{}{
+			map[string]interface{}{"id": float64(1), "name": "John"},
+			map[string]interface{}{"id": float64(2), "name": "Jane"},
+			map[string]interface{}{"id": float64(3), "name": "Bob"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		join      config.Join
+		expected  interface{}
+		completed bool
+	}{
+		{
+			name: "left join keeps unmatched items",
+			join: config.Join{Path: "users", Key: "id", ForeignKey: "user_id", Type: "left"},
+			expected: map[string]interface{}{
+				"users": []interface{}{
+					map[string]interface{}{"id": float64(1), "name": "John", "user_id": 1, "posts": 10},
+					map[string]interface{}{"id": float64(2), "name": "Jane"},
+					map[string]interface{}{"id": float64(3), "name": "Bob", "user_id": 3, "posts": 2},
+				},
+			},
+			completed: true,
+		},
+		{
+			name: "inner join drops unmatched items",
+			join: config.Join{Path: "users", Key: "id", ForeignKey: "user_id", Type: "inner"},
+			expected: map[string]interface{}{
+				"users": []interface{}{
+					map[string]interface{}{"id": float64(1), "name": "John", "user_id": 1, "posts": 10},
+					map[string]interface{}{"id": float64(3), "name": "Bob", "user_id": 3, "posts": 2},
+				},
+			},
+			completed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			join := tt.join
+			// The join backend is listed first to verify joins run after all other merges
+			responses := []types.BackendResponse{
+				{
+					Backend: config.Backend{Join: &join},
+					Data: []interface{}{
+						map[string]interface{}{"user_id": 1, "posts": 10},
+						map[string]interface{}{"user_id": 3, "posts": 2},
+						map[string]interface{}{"user_id": 4, "posts": 7},
+					},
+				},
+				users,
+			}
+
+			result, completed := merger.Merge(responses)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.completed, completed)
+		})
+	}
+}
+
+func TestMerger_Join_FailedBackend(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	responses := []types.BackendResponse{
+		{
+			Backend: config.Backend{Group: "users"},
+			Data: []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "John"},
+			},
+		},
+		{
+			Backend: config.Backend{Join: &config.Join{Path: "users", Key: "id", ForeignKey: "id", Type: "inner"}},
+			Error:   assert.AnError,
+		},
+	}
+
+	result, completed := merger.Merge(responses)
+	assert.False(t, completed)
+	assert.Equal(t, map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"id": float64(1), "name": "John"},
+		},
+	}, result)
+}
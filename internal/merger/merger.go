@@ -5,9 +5,13 @@ package merger
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/TrueTickets/api-aggregator/internal/config"
 	"github.com/TrueTickets/api-aggregator/internal/transformer"
 	"github.com/TrueTickets/api-aggregator/internal/types"
 )
@@ -33,15 +37,47 @@ func New(cfg Config) *Merger {
 	}
 }
 
-// Merge merges multiple backend responses into a single response
+// Merge merges multiple backend responses into a single response using the default merge policy
 func (m *Merger) Merge(responses []types.BackendResponse) (interface{}, bool) {
-	ctx := context.Background()
-	_, span := m.tracer.Start(ctx, "merge_responses")
+	result, allCompleted, _ := m.MergeEndpoint(context.Background(), config.Endpoint{}, responses)
+	return result, allCompleted
+}
+
+// MergeEndpoint merges backend responses using the endpoint's merge policy and applies its
+// post-merge collection operations. An error is returned if the error-on-conflict strategy is violated
+// or a concat path runs into a value that is not an object.
+func (m *Merger) MergeEndpoint(
+	ctx context.Context,
+	endpoint config.Endpoint,
+	responses []types.BackendResponse,
+) (interface{}, bool, error) {
+	ctx, span := m.tracer.Start(ctx, "merge_responses")
 	defer span.End()
 
+	state := newMergeState(endpoint.Merge, span)
+	result, allCompleted := m.mergeResponses(ctx, state, responses)
+	if state.err != nil {
+		return nil, allCompleted, state.err
+	}
+
+	// Apply collection operations to the merged result
+	if len(endpoint.Collections) > 0 {
+		result = m.transformer.ApplyCollections(ctx, result, endpoint.Collections)
+	}
+
+	return result, allCompleted, nil
+}
+
+// mergeResponses merges the transformed backend responses
+func (m *Merger) mergeResponses(
+	ctx context.Context,
+	state *mergeState,
+	responses []types.BackendResponse,
+) (interface{}, bool) {
 	result := make(map[string]interface{})
 	allCompleted := true
 	successfulResponses := 0
+	var joins []joinSource
 
 	for _, resp := range responses {
 		if resp.Error != nil {
@@ -57,15 +93,20 @@ func (m *Merger) Merge(responses []types.BackendResponse) (interface{}, bool) {
 
 		// Process the response data through transformations
 		processedData := m.transformer.Transform(ctx, resp.Data, resp.Backend)
+		state.backend = resp.Backend.Label()
 
 		// Merge the processed data based on backend configuration
 		switch {
+		case resp.Backend.Join != nil:
+			// Joins are applied once all other responses are merged so the target array is complete
+			joins = append(joins, joinSource{join: *resp.Backend.Join, backend: state.backend, data: processedData})
 		case resp.Backend.Concat != "":
-			// If concat is specified, append the data to an array under the specified key
-			m.appendToArray(result, resp.Backend.Concat, processedData)
+			// If concat is specified, append the data to an array under the specified path
+			m.appendToArray(state, result, resp.Backend.Concat, processedData)
 		case resp.Backend.Group != "":
-			// If group is specified, wrap the data in a group
-			result[resp.Backend.Group] = processedData
+			// If group is specified, wrap the data in a (possibly nested) group and merge it
+			// so that sibling groups under the same parent combine
+			m.mergeIntoResult(state, result, wrapInPath(resp.Backend.Group, processedData))
 		default:
 			// If we have only one successful response and no grouping,
 			// return the processed data directly (could be array, object, etc.)
@@ -73,16 +114,41 @@ func (m *Merger) Merge(responses []types.BackendResponse) (interface{}, bool) {
 				return processedData, allCompleted
 			}
 			// Otherwise, merge into result map
-			m.mergeIntoResult(result, processedData)
+			m.mergeIntoResult(state, result, processedData)
 		}
 	}
 
+	for _, source := range joins {
+		state.backend = source.backend
+		m.applyJoin(state, result, source.join, source.data)
+	}
+
 	return result, allCompleted
 }
 
-// appendToArray appends data to an array under the specified key in the result map
-// If data is an array, its elements are spread into the target array (flattened)
-func (m *Merger) appendToArray(result map[string]interface{}, key string, data interface{}) {
+// appendToArray appends data to an array at the specified dot-notation path in the result map
+// If data is an array, its elements are spread into the target array (flattened).
+// A non-object value in the way of the array fails the merge instead of dropping the data.
+func (m *Merger) appendToArray(state *mergeState, result map[string]interface{}, path string, data interface{}) {
+	parts := strings.Split(path, ".")
+	key := parts[len(parts)-1]
+
+	// Navigate to the parent map, creating intermediate maps as needed
+	for i, part := range parts[:len(parts)-1] {
+		next, exists := result[part]
+		if !exists {
+			next = make(map[string]interface{})
+			result[part] = next
+		}
+		nextMap, ok := next.(map[string]interface{})
+		if !ok {
+			state.fail(fmt.Errorf("cannot concat backend %s at %s: %s is not an object",
+				state.backend, path, strings.Join(parts[:i+1], ".")))
+			return
+		}
+		result = nextMap
+	}
+
 	existing, exists := result[key]
 
 	// Handle the case where data is an array - spread its elements
@@ -123,47 +189,147 @@ func (m *Merger) appendToArray(result map[string]interface{}, key string, data i
 }
 
 // mergeIntoResult merges data into the result map using deep merge
-func (m *Merger) mergeIntoResult(result map[string]interface{}, data interface{}) {
+func (m *Merger) mergeIntoResult(state *mergeState, result map[string]interface{}, data interface{}) {
 	dataMap, ok := data.(map[string]interface{})
 	if !ok {
 		return
 	}
 
 	for key, value := range dataMap {
-		m.deepMerge(result, key, value)
+		m.deepMerge(state, result, "", key, value)
 	}
 }
 
-// deepMerge performs a deep merge of the value into the result map at the given key
-func (m *Merger) deepMerge(result map[string]interface{}, key string, value interface{}) {
+// deepMerge performs a deep merge of the value into the result map at the given key.
+// The parent path locates the result map in the merged response for the merge policy.
+func (m *Merger) deepMerge(
+	state *mergeState,
+	result map[string]interface{},
+	parent, key string,
+	value interface{},
+) {
+	path := joinPath(parent, key)
+
 	existing, exists := result[key]
 	if !exists {
 		result[key] = value
+		state.setOwner(path)
 		return
 	}
 
+	policy := state.policyFor(path)
+
 	// Handle different merge scenarios based on types
 	switch existingVal := existing.(type) {
 	case map[string]interface{}:
 		if valueMap, ok := value.(map[string]interface{}); ok {
 			// Merge two maps recursively
 			for k, v := range valueMap {
-				m.deepMerge(existingVal, k, v)
+				m.deepMerge(state, existingVal, path, k, v)
 			}
-		} else {
-			// Replace if types don't match
-			result[key] = value
+			return
 		}
 	case []interface{}:
 		if valueSlice, ok := value.([]interface{}); ok {
-			// Combine arrays
-			result[key] = append(existingVal, valueSlice...)
-		} else {
-			// Replace if types don't match
-			result[key] = value
+			switch policy.Arrays {
+			case "replace":
+				// Resolve like a scalar conflict
+				m.resolveConflict(state, policy, result, path, key, value)
+			case "union":
+				result[key] = m.unionArrays(existingVal, valueSlice, policy.ArrayKey)
+			default:
+				// Combine arrays
+				result[key] = append(existingVal, valueSlice...)
+			}
+			return
 		}
-	default:
-		// For primitive types or mismatched types, replace
+	}
+
+	// For primitive types or mismatched types, resolve the conflict
+	m.resolveConflict(state, policy, result, path, key, value)
+}
+
+// resolveConflict decides whether the incoming value replaces the existing one according to the policy
+func (m *Merger) resolveConflict(
+	state *mergeState,
+	policy config.MergePolicy,
+	result map[string]interface{},
+	path, key string,
+	value interface{},
+) {
+	existing := result[key]
+	if reflect.DeepEqual(existing, value) {
+		return
+	}
+
+	owner := state.ownerOf(path)
+	replace := true
+
+	switch policy.Strategy {
+	case "first_wins":
+		replace = false
+	case "prefer_backend":
+		// The preferred backend wins; between other backends the last one wins
+		replace = state.backend == policy.PreferBackend || owner != policy.PreferBackend
+	case "error":
+		replace = false
+		state.fail(fmt.Errorf("merge conflict at %s between backends %s and %s", path, owner, state.backend))
+	}
+
+	state.recordConflict(path, owner, replace)
+
+	if replace {
 		result[key] = value
+		state.setOwner(path)
+	}
+}
+
+// unionArrays appends the incoming items whose identity is not already present.
+// Items are identified by the key field, or by their whole value if no key is configured.
+func (m *Merger) unionArrays(existing, incoming []interface{}, keyField string) []interface{} {
+	seen := make(map[string]bool, len(existing))
+	identity := func(item interface{}) (string, bool) {
+		if keyField == "" {
+			return transformer.ValueKey(item), true
+		}
+		return m.transformer.ItemKey(item, keyField)
+	}
+
+	for _, item := range existing {
+		if key, ok := identity(item); ok {
+			seen[key] = true
+		}
+	}
+
+	result := existing
+	for _, item := range incoming {
+		key, ok := identity(item)
+		if ok && seen[key] {
+			continue
+		}
+		if ok {
+			seen[key] = true
+		}
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// wrapInPath wraps data in nested maps following a dot-notation path
+func wrapInPath(path string, data interface{}) map[string]interface{} {
+	parts := strings.Split(path, ".")
+	wrapped := map[string]interface{}{parts[len(parts)-1]: data}
+	for i := len(parts) - 2; i >= 0; i-- {
+		wrapped = map[string]interface{}{parts[i]: wrapped}
+	}
+	return wrapped
+}
+
+// joinPath appends a key to a dot-notation path
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
 	}
+	return parent + "." + key
 }
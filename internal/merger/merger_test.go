@@ -4,9 +4,13 @@
 package merger
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/TrueTickets/api-aggregator/internal/config"
@@ -416,6 +420,58 @@ func TestMerger_Merge(t *testing.T) {
 			},
 			completed: true,
 		},
+		{
+			name: "nested groups under the same parent combine",
+			responses: []types.BackendResponse{
+				{
+					Backend: config.Backend{Group: "event.venue"},
+					Data:    map[string]interface{}{"name": "Arena"},
+				},
+				{
+					Backend: config.Backend{Group: "event.performers"},
+					Data:    []interface{}{"Band A", "Band B"},
+				},
+				{
+					Backend: config.Backend{Group: "event"},
+					Data:    map[string]interface{}{"id": 7},
+				},
+			},
+			expected: map[string]interface{}{
+				"event": map[string]interface{}{
+					"id":         7,
+					"venue":      map[string]interface{}{"name": "Arena"},
+					"performers": []interface{}{"Band A", "Band B"},
+				},
+			},
+			completed: true,
+		},
+		{
+			name: "nested concat destination",
+			responses: []types.BackendResponse{
+				{
+					Backend: config.Backend{Group: "feed"},
+					Data:    map[string]interface{}{"title": "Activity"},
+				},
+				{
+					Backend: config.Backend{Concat: "feed.items"},
+					Data:    []interface{}{map[string]interface{}{"id": 1}},
+				},
+				{
+					Backend: config.Backend{Concat: "feed.items"},
+					Data:    map[string]interface{}{"id": 2},
+				},
+			},
+			expected: map[string]interface{}{
+				"feed": map[string]interface{}{
+					"title": "Activity",
+					"items": []interface{}{
+						map[string]interface{}{"id": 1},
+						map[string]interface{}{"id": 2},
+					},
+				},
+			},
+			completed: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -426,3 +482,213 @@ func TestMerger_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestMerger_MergeEndpoint_Collections(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	endpoint := config.Endpoint{
+		Collections: []config.CollectionOperation{
+			{Path: "activities", Dedupe: "id"},
+			{Path: "activities", Sort: []config.SortField{{Field: "id", Order: "desc"}}},
+		},
+	}
+
+	responses := []types.BackendResponse{
+		{
+			Backend: config.Backend{Concat: "activities"},
+			Data: []interface{}{
+				map[string]interface{}{"id": 1, "title": "post"},
+				map[string]interface{}{"id": 3, "title": "post"},
+			},
+		},
+		{
+			Backend: config.Backend{Concat: "activities"},
+			Data: []interface{}{
+				map[string]interface{}{"id": 3, "title": "todo"},
+				map[string]interface{}{"id": 2, "title": "todo"},
+			},
+		},
+	}
+
+	result, completed, err := merger.MergeEndpoint(context.Background(), endpoint, responses)
+	require.NoError(t, err)
+	assert.True(t, completed)
+	assert.Equal(t, map[string]interface{}{
+		"activities": []interface{}{
+			map[string]interface{}{"id": 3, "title": "post"},
+			map[string]interface{}{"id": 2, "title": "todo"},
+			map[string]interface{}{"id": 1, "title": "post"},
+		},
+	}, result)
+}
+
+func TestMerger_MergeEndpoint_Strategies(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	responses := []types.BackendResponse{
+		{
+			Backend: config.Backend{Name: "primary"},
+			Data: map[string]interface{}{
+				"name": "John",
+				"tags": []interface{}{"a", "b"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "source": "primary"},
+				},
+			},
+		},
+		{
+			Backend: config.Backend{Name: "secondary"},
+			Data: map[string]interface{}{
+				"name": "Johnny",
+				"tags": []interface{}{"b", "c"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "source": "secondary"},
+					map[string]interface{}{"id": 2, "source": "secondary"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		options  config.MergeOptions
+		expected map[string]interface{}
+	}{
+		{
+			name:    "first wins with array replace",
+			options: config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "first_wins", Arrays: "replace"}},
+			expected: map[string]interface{}{
+				"name": "John",
+				"tags": []interface{}{"a", "b"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "source": "primary"},
+				},
+			},
+		},
+		{
+			name:    "last wins with array replace",
+			options: config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "last_wins", Arrays: "replace"}},
+			expected: map[string]interface{}{
+				"name": "Johnny",
+				"tags": []interface{}{"b", "c"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "source": "secondary"},
+					map[string]interface{}{"id": 2, "source": "secondary"},
+				},
+			},
+		},
+		{
+			name: "prefer backend with union and path override",
+			options: config.MergeOptions{
+				MergePolicy: config.MergePolicy{Strategy: "prefer_backend", PreferBackend: "primary", Arrays: "union"},
+				Paths: []config.PathMergePolicy{
+					{Path: "items", MergePolicy: config.MergePolicy{Arrays: "union", ArrayKey: "id"}},
+				},
+			},
+			expected: map[string]interface{}{
+				"name": "John",
+				"tags": []interface{}{"a", "b", "c"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "source": "primary"},
+					map[string]interface{}{"id": 2, "source": "secondary"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Copy response data since merging mutates it
+			copied := make([]types.BackendResponse, len(responses))
+			for i, resp := range responses {
+				copied[i] = resp
+				data := make(map[string]interface{})
+				for k, v := range resp.Data.(map[string]interface{}) {
+					if slice, ok := v.([]interface{}); ok {
+						v = append([]interface{}{}, slice...)
+					}
+					data[k] = v
+				}
+				copied[i].Data = data
+			}
+
+			result, completed, err := merger.MergeEndpoint(context.Background(),
+				config.Endpoint{Merge: tt.options}, copied)
+			require.NoError(t, err)
+			assert.True(t, completed)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMerger_MergeEndpoint_ErrorOnConflict(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	endpoint := config.Endpoint{
+		Merge: config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "error", Arrays: "append"}},
+	}
+
+	// Equal values are not conflicts
+	_, _, err := merger.MergeEndpoint(context.Background(), endpoint, []types.BackendResponse{
+		{Backend: config.Backend{Name: "a"}, Data: map[string]interface{}{"id": 1}},
+		{Backend: config.Backend{Name: "b"}, Data: map[string]interface{}{"id": 1}},
+	})
+	require.NoError(t, err)
+
+	_, _, err = merger.MergeEndpoint(context.Background(), endpoint, []types.BackendResponse{
+		{Backend: config.Backend{Name: "a"}, Data: map[string]interface{}{"user": map[string]interface{}{"id": 1}}},
+		{Backend: config.Backend{Name: "b"}, Data: map[string]interface{}{"user": map[string]interface{}{"id": 2}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "merge conflict at user.id between backends a and b")
+}
+
+func TestMerger_MergeEndpoint_ConcatBlocked(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	// The feed value of the first backend is in the way of the concat path
+	_, _, err := merger.MergeEndpoint(context.Background(), config.Endpoint{}, []types.BackendResponse{
+		{Backend: config.Backend{Name: "a"}, Data: map[string]interface{}{"feed": "disabled"}},
+		{Backend: config.Backend{Name: "b", Concat: "feed.items"}, Data: map[string]interface{}{"id": 1}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot concat backend b at feed.items: feed is not an object")
+}
+
+func TestMerger_MergeEndpoint_RecordConflicts(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	merger := New(Config{Tracer: tracer})
+
+	endpoint := config.Endpoint{
+		Merge: config.MergeOptions{
+			MergePolicy:     config.MergePolicy{Strategy: "first_wins", Arrays: "append"},
+			RecordConflicts: true,
+		},
+	}
+
+	_, _, err := merger.MergeEndpoint(context.Background(), endpoint, []types.BackendResponse{
+		{Backend: config.Backend{Name: "a"}, Data: map[string]interface{}{"status": "active"}},
+		{Backend: config.Backend{Name: "b"}, Data: map[string]interface{}{"status": "inactive"}},
+	})
+	require.NoError(t, err)
+
+	var events []string
+	for _, span := range recorder.Ended() {
+		if span.Name() != "merge_responses" {
+			continue
+		}
+		for _, event := range span.Events() {
+			attrs := make(map[string]string)
+			for _, attr := range event.Attributes {
+				attrs[string(attr.Key)] = attr.Value.AsString()
+			}
+			events = append(events, event.Name+":"+attrs["merge.path"]+":"+attrs["merge.resolution"])
+		}
+	}
+	assert.Equal(t, []string{"merge_conflict:status:kept"}, events)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package merger
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// mergeState tracks the merge policy and value ownership while merging a single request
+type mergeState struct {
+	options config.MergeOptions
+	span    trace.Span
+
+	// backend is the label of the backend currently being merged
+	backend string
+
+	// owners maps paths in the merged response to the backend that wrote them, when a
+	// strategy or conflict recording needs to know; nil otherwise
+	owners map[string]owner
+
+	// writes counts ownership changes, so the latest write of a path or its ancestors wins
+	writes int
+
+	// err holds the first conflict error when the error strategy is used
+	err error
+}
+
+// owner is the backend that wrote a path and when
+type owner struct {
+	backend string
+	write   int
+}
+
+// newMergeState creates merge state for the given options
+func newMergeState(options config.MergeOptions, span trace.Span) *mergeState {
+	state := &mergeState{
+		options: options,
+		span:    span,
+	}
+	if tracksOwners(options) {
+		state.owners = make(map[string]owner)
+	}
+	return state
+}
+
+// tracksOwners reports whether the options need the backend owning each path: to prefer a
+// backend, to name both backends in conflict errors, or to record conflicts
+func tracksOwners(options config.MergeOptions) bool {
+	if options.RecordConflicts || needsOwner(options.Strategy) {
+		return true
+	}
+	for _, path := range options.Paths {
+		if needsOwner(path.Strategy) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsOwner reports whether a strategy depends on the backend owning a path
+func needsOwner(strategy string) bool {
+	return strategy == "prefer_backend" || strategy == "error"
+}
+
+// policyFor returns the effective merge policy for a path, applying the most specific override
+func (s *mergeState) policyFor(path string) config.MergePolicy {
+	policy := s.options.MergePolicy
+
+	best := -1
+	var override *config.PathMergePolicy
+	for i := range s.options.Paths {
+		candidate := &s.options.Paths[i]
+		if path != candidate.Path && !strings.HasPrefix(path, candidate.Path+".") {
+			continue
+		}
+		if len(candidate.Path) > best {
+			best = len(candidate.Path)
+			override = candidate
+		}
+	}
+
+	if override != nil {
+		if override.Strategy != "" {
+			policy.Strategy = override.Strategy
+			policy.PreferBackend = override.PreferBackend
+		}
+		if override.Arrays != "" {
+			policy.Arrays = override.Arrays
+			policy.ArrayKey = override.ArrayKey
+		}
+	}
+
+	return policy
+}
+
+// setOwner records the current backend as the owner of a path, replacing ownership below it
+func (s *mergeState) setOwner(path string) {
+	if s.owners == nil {
+		return
+	}
+	s.writes++
+	s.owners[path] = owner{backend: s.backend, write: s.writes}
+}
+
+// ownerOf returns the backend that last wrote a path or one of its ancestors
+func (s *mergeState) ownerOf(path string) string {
+	var latest owner
+	for {
+		if candidate, ok := s.owners[path]; ok && candidate.write > latest.write {
+			latest = candidate
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return latest.backend
+		}
+		path = path[:i]
+	}
+}
+
+// recordConflict adds a trace event for a conflict if conflict recording is enabled
+func (s *mergeState) recordConflict(path, existingBackend string, replaced bool) {
+	if !s.options.RecordConflicts {
+		return
+	}
+
+	resolution := "kept"
+	if replaced {
+		resolution = "replaced"
+	}
+
+	s.span.AddEvent("merge_conflict", trace.WithAttributes(
+		attribute.String("merge.path", path),
+		attribute.String("merge.existing_backend", existingBackend),
+		attribute.String("merge.incoming_backend", s.backend),
+		attribute.String("merge.resolution", resolution),
+	))
+}
+
+// fail records the first merge error
+func (s *mergeState) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestMergeState_Owners(t *testing.T) {
+	_, span := noop.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	state := newMergeState(config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "error"}}, span)
+
+	state.backend = "users"
+	state.setOwner("user")
+	state.setOwner("user.name")
+	assert.Equal(t, "users", state.ownerOf("user.name"))
+	assert.Equal(t, "users", state.ownerOf("user.email"))
+
+	// Replacing a path replaces the ownership below it
+	state.backend = "profiles"
+	state.setOwner("user")
+	assert.Equal(t, "profiles", state.ownerOf("user.name"))
+
+	// Later writes below a path take precedence again
+	state.backend = "accounts"
+	state.setOwner("user.name")
+	assert.Equal(t, "accounts", state.ownerOf("user.name"))
+	assert.Equal(t, "profiles", state.ownerOf("user.email"))
+	assert.Empty(t, state.ownerOf("orders"))
+}
+
+func TestTracksOwners(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  config.MergeOptions
+		expected bool
+	}{
+		{
+			name:     "last wins",
+			options:  config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "last_wins"}},
+			expected: false,
+		},
+		{
+			name:     "first wins",
+			options:  config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "first_wins"}},
+			expected: false,
+		},
+		{
+			name:     "prefer backend",
+			options:  config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "prefer_backend"}},
+			expected: true,
+		},
+		{
+			name:     "error",
+			options:  config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "error"}},
+			expected: true,
+		},
+		{
+			name: "path override",
+			options: config.MergeOptions{
+				MergePolicy: config.MergePolicy{Strategy: "last_wins"},
+				Paths: []config.PathMergePolicy{
+					{Path: "user", MergePolicy: config.MergePolicy{Strategy: "prefer_backend"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "record conflicts",
+			options: config.MergeOptions{
+				MergePolicy:     config.MergePolicy{Strategy: "last_wins"},
+				RecordConflicts: true,
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tracksOwners(tt.options))
+		})
+	}
+}
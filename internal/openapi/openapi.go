@@ -0,0 +1,459 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package openapi generates an OpenAPI 3.1 document describing the configured endpoints.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TrueTickets/api-aggregator/internal/build"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// Version is the OpenAPI specification version of generated documents
+const Version = "3.1.0"
+
+// Names of the shared components
+const (
+	errorSchemaName           = "Error"
+	validationErrorSchemaName = "ValidationError"
+	bearerSchemeName          = "bearerAuth"
+)
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL of the API
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations of a path, by lower-case HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes an endpoint
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Name     string                 `json:"name"`
+	In       string                 `json:"in"`
+	Required bool                   `json:"required"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+// RequestBody describes the request body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema of a request or response body
+type MediaType struct {
+	Schema interface{} `json:"schema"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header
+type Header struct {
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+// Components holds the schemas and security schemes referenced by operations
+type Components struct {
+	Schemas         map[string]interface{}    `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how callers authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// pathParamPattern matches the parameters of a route pattern, with an optional regular expression
+var pathParamPattern = regexp.MustCompile(`\{([^{}:]+)(?::([^{}]+))?\}`)
+
+// nonWordPattern matches the separators between the words of an operation identifier
+var nonWordPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Generate builds the OpenAPI document for a configuration. Request schema files are read,
+// so errors are only returned if they changed since the configuration was loaded.
+func Generate(cfg *config.Config) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       cfg.OpenAPI.Title,
+			Version:     cfg.OpenAPI.Version,
+			Description: cfg.OpenAPI.Description,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: map[string]interface{}{
+				errorSchemaName:           errorSchema(),
+				validationErrorSchemaName: validationErrorSchema(),
+			},
+		},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = cfg.ServiceName
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = build.ServiceVersion
+	}
+	for _, url := range cfg.OpenAPI.Servers {
+		doc.Servers = append(doc.Servers, Server{URL: url})
+	}
+
+	for _, endpoint := range cfg.Endpoints {
+		path := pathParamPattern.ReplaceAllString(endpoint.Endpoint, "{$1}")
+		operation, err := doc.operation(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", endpoint.Endpoint, err)
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(endpoint.Method)] = operation
+	}
+
+	return doc, nil
+}
+
+// operation describes a configured endpoint
+func (d *Document) operation(endpoint config.Endpoint) (*Operation, error) {
+	op := &Operation{
+		OperationID: operationID(endpoint),
+		Summary:     endpoint.Summary,
+		Description: endpoint.Description,
+		Tags:        endpoint.Tags,
+		Parameters:  parameters(endpoint),
+		Responses:   make(map[string]Response),
+	}
+
+	requestSchema, err := endpoint.LoadRequestSchema()
+	if err != nil {
+		return nil, err
+	}
+	if config.AllowsRequestBody(endpoint.Method) {
+		op.RequestBody = &RequestBody{
+			Required: requestSchema != nil,
+			Content: map[string]MediaType{
+				"application/json": {Schema: d.embedSchema(op.OperationID+"Request", requestSchema)},
+			},
+		}
+	}
+
+	var responseSchema interface{}
+	if endpoint.ResponseSchema != nil {
+		responseSchema = endpoint.ResponseSchema
+	}
+	op.Responses["200"] = Response{
+		Description: "Aggregated response of the backends",
+		Headers:     responseHeaders(endpoint),
+		Content: map[string]MediaType{
+			"application/json": {Schema: d.embedSchema(op.OperationID+"Response", responseSchema)},
+		},
+	}
+	if requestSchema != nil || len(endpoint.PathParams) > 0 || len(endpoint.QueryParams) > 0 {
+		op.Responses["400"] = errorResponse("The request is invalid", validationErrorSchemaName)
+	}
+	op.Responses["500"] = errorResponse("All backends failed", errorSchemaName)
+
+	if usesCallerCredentials(endpoint) {
+		d.addBearerScheme()
+		op.Security = []map[string][]string{{bearerSchemeName: {}}}
+	}
+
+	return op, nil
+}
+
+// parameters describes the path parameters of an endpoint, in path order, then its query parameters
+func parameters(endpoint config.Endpoint) []Parameter {
+	var params []Parameter
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(endpoint.Endpoint, -1) {
+		name, routePattern := match[1], match[2]
+		schema := paramSchema(endpoint.PathParams[name])
+		if routePattern != "" {
+			if _, constrained := schema["pattern"]; !constrained {
+				schema["pattern"] = "^" + routePattern + "$"
+			}
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	names := make([]string, 0, len(endpoint.QueryParams))
+	for name := range endpoint.QueryParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		constraint := endpoint.QueryParams[name]
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: constraint.Required,
+			Schema:   paramSchema(constraint),
+		})
+	}
+
+	return params
+}
+
+// paramSchema converts a parameter constraint into a schema
+func paramSchema(constraint config.ParamConstraint) map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+	switch constraint.Type {
+	case "int":
+		schema["type"] = "integer"
+	case "number", "boolean":
+		schema["type"] = constraint.Type
+	case "uuid":
+		schema["format"] = "uuid"
+	}
+	if constraint.Pattern != "" {
+		schema["pattern"] = constraint.Pattern
+	}
+	if len(constraint.Enum) > 0 {
+		schema["enum"] = constraint.Enum
+	}
+	return schema
+}
+
+// responseHeaders describes the headers of a successful response
+func responseHeaders(endpoint config.Endpoint) map[string]Header {
+	headers := map[string]Header{
+		"X-API-Aggregation-Completed": {
+			Description: "Whether every backend responded",
+			Schema:      map[string]interface{}{"type": "string", "enum": []string{"true", "false"}},
+		},
+		"X-API-Aggregation-Fallback": {
+			Description: "Backends whose response came from a fallback",
+			Schema:      map[string]interface{}{"type": "string"},
+		},
+	}
+
+	for _, backend := range endpoint.Backends {
+		for _, rule := range backend.ExposeHeaders {
+			name := rule.Name
+			if rule.As != "" {
+				name = rule.As
+			}
+			// Prefix rules expose headers that cannot be named in advance
+			if !strings.HasSuffix(name, "*") {
+				headers[http.CanonicalHeaderKey(name)] = Header{Schema: map[string]interface{}{"type": "string"}}
+			}
+		}
+	}
+	for name := range endpoint.ResponseHeaders {
+		headers[http.CanonicalHeaderKey(name)] = Header{Schema: map[string]interface{}{"type": "string"}}
+	}
+
+	return headers
+}
+
+// usesCallerCredentials reports whether an endpoint passes the caller's bearer token or
+// its claims to a backend, so callers are expected to authenticate
+func usesCallerCredentials(endpoint config.Endpoint) bool {
+	for _, backend := range endpoint.Backends {
+		if backend.Auth == nil {
+			for _, name := range backend.ForwardHeaders {
+				if strings.EqualFold(name, "Authorization") {
+					return true
+				}
+			}
+		}
+
+		templates := make([]string, 0, len(backend.SetHeaders)+len(backend.AddHeaders))
+		for _, template := range backend.SetHeaders {
+			templates = append(templates, template)
+		}
+		for _, template := range backend.AddHeaders {
+			templates = append(templates, template)
+		}
+		if backend.Body != nil {
+			for _, value := range backend.Body.Set {
+				if template, ok := value.(string); ok {
+					templates = append(templates, template)
+				}
+			}
+		}
+		for _, template := range templates {
+			for _, match := range config.TemplatePattern.FindAllStringSubmatch(template, -1) {
+				if match[1] == "claims" || (match[1] == "header" && strings.EqualFold(match[2], "Authorization")) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addBearerScheme adds the bearer token security scheme
+func (d *Document) addBearerScheme() {
+	d.Components.SecuritySchemes = map[string]SecurityScheme{
+		bearerSchemeName: {
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+			Description:  "Caller credentials, forwarded to the backends",
+		},
+	}
+}
+
+// embedSchema returns a schema to embed in an operation. Definitions are moved to the
+// components, prefixed with the given name, as $ref is resolved against the whole document.
+func (d *Document) embedSchema(prefix string, document interface{}) interface{} {
+	if document == nil {
+		return map[string]interface{}{}
+	}
+
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return document
+	}
+	defs, _ := root["$defs"].(map[string]interface{})
+
+	rewrite := func(value interface{}) interface{} {
+		return rewriteRefs(value, func(name string) string {
+			return "#/components/schemas/" + prefix + name
+		})
+	}
+
+	for name, def := range defs {
+		d.Components.Schemas[prefix+name] = rewrite(def)
+	}
+
+	embedded := make(map[string]interface{}, len(root))
+	for key, value := range root {
+		if key != "$defs" && key != "$schema" && key != "$id" {
+			embedded[key] = rewrite(value)
+		}
+	}
+	return embedded
+}
+
+// rewriteRefs copies a schema, replacing references to local definitions
+func rewriteRefs(value interface{}, target func(name string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if ref, isString := item.(string); key == "$ref" && isString {
+				if name, local := strings.CutPrefix(ref, "#/$defs/"); local {
+					copied[key] = target(name)
+					continue
+				}
+			}
+			copied[key] = rewriteRefs(item, target)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = rewriteRefs(item, target)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// errorResponse describes an error response
+func errorResponse(description, schemaName string) Response {
+	return Response{
+		Description: description,
+		Content: map[string]MediaType{
+			"application/json": {Schema: map[string]interface{}{"$ref": "#/components/schemas/" + schemaName}},
+		},
+	}
+}
+
+// errorSchema is the schema of error responses
+func errorSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": map[string]interface{}{
+			"error": map[string]interface{}{"type": "string"},
+		},
+	}
+}
+
+// validationErrorSchema is the schema of request validation failures
+func validationErrorSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"error", "errors"},
+		"properties": map[string]interface{}{
+			"error": map[string]interface{}{"type": "string"},
+			"errors": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"location", "message"},
+					"properties": map[string]interface{}{
+						"location": map[string]interface{}{"type": "string", "enum": []string{"path", "query", "body"}},
+						"field": map[string]interface{}{
+							"type":        "string",
+							"description": "Parameter name, or JSON Pointer into the body",
+						},
+						"message": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// operationID derives an operation identifier from the method and path of an endpoint
+func operationID(endpoint config.Endpoint) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(endpoint.Method))
+
+	path := pathParamPattern.ReplaceAllString(endpoint.Endpoint, "By/$1")
+	for _, word := range nonWordPattern.Split(path, -1) {
+		if word != "" {
+			id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return id.String()
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/build"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestGenerate(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "api-aggregator",
+		OpenAPI: config.OpenAPI{
+			Enabled:     true,
+			Description: "Aggregated API",
+			Servers:     []string{"https://api.example.com"},
+		},
+		Endpoints: []config.Endpoint{
+			{
+				Endpoint:    "/users/{id:[0-9]+}/orders",
+				Method:      http.MethodGet,
+				Summary:     "List orders",
+				Description: "Orders of a user",
+				Tags:        []string{"orders"},
+				QueryParams: map[string]config.ParamConstraint{
+					"status": {Type: "string", Enum: []string{"open", "closed"}},
+					"limit":  {Type: "int", Required: true},
+				},
+				ResponseSchema: map[string]interface{}{"type": "object"},
+				Backends: []config.Backend{
+					{
+						Host:           "http://orders",
+						URLPattern:     "/orders",
+						ForwardHeaders: []string{"Authorization"},
+						ExposeHeaders:  []config.ExposeHeader{{Name: "X-Request-Id"}, {Name: "X-Rate-*"}},
+					},
+				},
+			},
+			{
+				Endpoint: "/orders",
+				Method:   http.MethodPost,
+				RequestSchema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"item": map[string]interface{}{"$ref": "#/$defs/item"}},
+					"$defs":      map[string]interface{}{"item": map[string]interface{}{"type": "string"}},
+				},
+				Backends: []config.Backend{{Host: "http://orders", URLPattern: "/orders"}},
+			},
+		},
+	}
+
+	doc, err := Generate(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, Info{Title: "api-aggregator", Version: build.ServiceVersion, Description: "Aggregated API"}, doc.Info)
+	assert.Equal(t, []Server{{URL: "https://api.example.com"}}, doc.Servers)
+
+	get := doc.Paths["/users/{id}/orders"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "getUsersByIdOrders", get.OperationID)
+	assert.Equal(t, "List orders", get.Summary)
+	assert.Equal(t, "Orders of a user", get.Description)
+	assert.Equal(t, []string{"orders"}, get.Tags)
+	assert.Nil(t, get.RequestBody)
+	assert.Equal(t, []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "string", "pattern": "^[0-9]+$"}},
+		{Name: "limit", In: "query", Required: true, Schema: map[string]interface{}{"type": "integer"}},
+		{Name: "status", In: "query", Schema: map[string]interface{}{"type": "string", "enum": []string{"open", "closed"}}},
+	}, get.Parameters)
+	assert.Contains(t, get.Responses, "400")
+	assert.Contains(t, get.Responses, "500")
+	assert.Equal(t, map[string]interface{}{"type": "object"}, get.Responses["200"].Content["application/json"].Schema)
+	assert.Contains(t, get.Responses["200"].Headers, "X-Request-Id")
+	assert.NotContains(t, get.Responses["200"].Headers, "X-Rate-*")
+	assert.Equal(t, []map[string][]string{{bearerSchemeName: {}}}, get.Security)
+	assert.Contains(t, doc.Components.SecuritySchemes, bearerSchemeName)
+
+	post := doc.Paths["/orders"]["post"]
+	require.NotNil(t, post)
+	assert.Equal(t, "postOrders", post.OperationID)
+	assert.Empty(t, post.Security)
+	require.NotNil(t, post.RequestBody)
+	assert.True(t, post.RequestBody.Required)
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"item": map[string]interface{}{"$ref": "#/components/schemas/postOrdersRequestitem"},
+		},
+	}, post.RequestBody.Content["application/json"].Schema)
+	assert.Equal(t, map[string]interface{}{"type": "string"}, doc.Components.Schemas["postOrdersRequestitem"])
+	assert.Contains(t, post.Responses, "400")
+
+	// The document must serialize as JSON
+	_, err = json.Marshal(doc)
+	require.NoError(t, err)
+}
+
+func TestGenerate_CallerCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  config.Backend
+		expected bool
+	}{
+		{
+			name:     "no credentials",
+			backend:  config.Backend{ForwardHeaders: []string{"X-Request-Id"}},
+			expected: false,
+		},
+		{
+			name:     "forwarded authorization",
+			backend:  config.Backend{ForwardHeaders: []string{"authorization"}},
+			expected: true,
+		},
+		{
+			name: "forwarded authorization replaced by backend auth",
+			backend: config.Backend{
+				ForwardHeaders: []string{"Authorization"},
+				Auth:           &config.Auth{Type: "bearer", Token: config.Secret{Value: "secret"}},
+			},
+			expected: false,
+		},
+		{
+			name:     "claims template",
+			backend:  config.Backend{SetHeaders: map[string]string{"X-User": "{claims.sub}"}},
+			expected: true,
+		},
+		{
+			name: "authorization in body template",
+			backend: config.Backend{
+				Body: &config.RequestBody{Set: map[string]interface{}{"token": "{header.Authorization}"}},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, usesCallerCredentials(config.Endpoint{Backends: []config.Backend{tt.backend}}))
+		})
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodGet, path: "/users", expected: "getUsers"},
+		{method: http.MethodGet, path: "/users/{id}", expected: "getUsersById"},
+		{method: http.MethodDelete, path: "/user-profiles/{profile_id:[a-z]+}", expected: "deleteUserProfilesByProfileId"},
+		{method: http.MethodGet, path: "/", expected: "get"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, operationID(config.Endpoint{Method: tt.method, Endpoint: tt.path}))
+		})
+	}
+}
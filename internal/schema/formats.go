@@ -0,0 +1,50 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package schema
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// uuidPattern matches UUIDs in their canonical textual form
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formats are the supported string formats
+var formats = map[string]func(string) bool{
+	"uuid": uuidPattern.MatchString,
+	"email": func(value string) bool {
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	},
+	"date-time": func(value string) bool {
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	},
+	"date": func(value string) bool {
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	},
+	"ipv4": func(value string) bool {
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is4()
+	},
+	"ipv6": func(value string) bool {
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is6()
+	},
+	"uri": func(value string) bool {
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.IsAbs()
+	},
+}
+
+// ValidFormat reports whether a string has a supported format
+func ValidFormat(format, value string) bool {
+	check, supported := formats[format]
+	return supported && check(value)
+}
@@ -0,0 +1,675 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package schema validates decoded JSON values against a subset of JSON Schema draft 2020-12.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is a validation failure of a value or one of its members
+type Error struct {
+	// JSON Pointer to the invalid value, empty for the validated value itself
+	Path string
+
+	Message string
+}
+
+// Error implements the error interface
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	// Boolean schemas accept or reject every value
+	reject bool
+
+	ref *Schema
+
+	types      []string
+	enum       []interface{}
+	constValue interface{}
+	hasConst   bool
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	format               string
+
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+	multipleOf                         *float64
+
+	prefixItems        []*Schema
+	items              *Schema
+	minItems, maxItems *int
+	uniqueItems        bool
+
+	properties                   map[string]*Schema
+	required                     []string
+	additionalProperties         *Schema
+	minProperties, maxProperties *int
+
+	allOf, anyOf, oneOf []*Schema
+	not                 *Schema
+}
+
+// annotations are keywords accepted without affecting validation
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// validTypes are the JSON Schema type names
+var validTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+// compiler holds the definitions referenced while compiling a document
+type compiler struct {
+	defs map[string]*Schema
+}
+
+// Compile compiles a JSON Schema document, an object or a boolean, as decoded from JSON or YAML.
+// References are limited to definitions in the document's top-level $defs.
+func Compile(document interface{}) (*Schema, error) {
+	c := &compiler{defs: make(map[string]*Schema)}
+
+	if root, ok := document.(map[string]interface{}); ok {
+		if defs, exists := root["$defs"]; exists {
+			defsMap, ok := defs.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema /$defs: must be an object")
+			}
+
+			// Placeholders are created first so definitions can refer to each other
+			for name := range defsMap {
+				c.defs[name] = &Schema{}
+			}
+			for _, name := range sortedKeys(defsMap) {
+				def, err := c.compile(defsMap[name], "/$defs/"+escapePointer(name))
+				if err != nil {
+					return nil, err
+				}
+				*c.defs[name] = *def
+			}
+
+			for _, name := range sortedKeys(defsMap) {
+				if refCycle(c.defs[name], make(map[*Schema]bool), make(map[*Schema]bool)) {
+					return nil, schemaError("/$defs/"+escapePointer(name),
+						"reference cycle without a properties or items step")
+				}
+			}
+		}
+	}
+
+	return c.compileRoot(document)
+}
+
+// refCycle reports whether a schema reaches itself through keywords applying to the same value
+// ($ref, allOf, anyOf, oneOf and not). Validation would never end on such a cycle; cycles
+// through properties or items are fine, as they descend into a smaller value.
+func refCycle(s *Schema, visiting, done map[*Schema]bool) bool {
+	if s == nil || done[s] {
+		return false
+	}
+	if visiting[s] {
+		return true
+	}
+	visiting[s] = true
+
+	next := []*Schema{s.ref, s.not}
+	next = append(next, s.allOf...)
+	next = append(next, s.anyOf...)
+	next = append(next, s.oneOf...)
+	for _, child := range next {
+		if refCycle(child, visiting, done) {
+			return true
+		}
+	}
+
+	visiting[s] = false
+	done[s] = true
+	return false
+}
+
+// compileRoot compiles the top-level schema, the only one allowed to hold $defs
+func (c *compiler) compileRoot(document interface{}) (*Schema, error) {
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return c.compile(document, "")
+	}
+
+	withoutDefs := make(map[string]interface{}, len(root))
+	for key, value := range root {
+		if key != "$defs" {
+			withoutDefs[key] = value
+		}
+	}
+	return c.compile(withoutDefs, "")
+}
+
+// compile compiles the schema at path in the document
+func (c *compiler) compile(document interface{}, path string) (*Schema, error) {
+	switch doc := document.(type) {
+	case bool:
+		return &Schema{reject: !doc}, nil
+	case map[string]interface{}:
+		s := &Schema{}
+		for _, key := range sortedKeys(doc) {
+			if err := c.compileKeyword(s, key, doc[key], path); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	default:
+		return nil, schemaError(path, "must be an object or a boolean")
+	}
+}
+
+// compileKeyword compiles a single keyword into s
+func (c *compiler) compileKeyword(s *Schema, key string, value interface{}, path string) error {
+	keywordPath := path + "/" + escapePointer(key)
+	var err error
+
+	switch key {
+	case "$ref":
+		ref, _ := value.(string)
+		name, found := strings.CutPrefix(ref, "#/$defs/")
+		if !found || c.defs[name] == nil {
+			return schemaError(keywordPath, "unsupported reference %v, only #/$defs/<name> is supported", value)
+		}
+		s.ref = c.defs[name]
+	case "type":
+		s.types, err = compileTypes(value, keywordPath)
+	case "enum":
+		items, ok := value.([]interface{})
+		if !ok || len(items) == 0 {
+			return schemaError(keywordPath, "must be a non-empty array")
+		}
+		for _, item := range items {
+			s.enum = append(s.enum, normalize(item))
+		}
+	case "const":
+		s.constValue = normalize(value)
+		s.hasConst = true
+	case "minLength":
+		s.minLength, err = compileCount(value, keywordPath)
+	case "maxLength":
+		s.maxLength, err = compileCount(value, keywordPath)
+	case "pattern":
+		pattern, ok := value.(string)
+		if !ok {
+			return schemaError(keywordPath, "must be a string")
+		}
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return schemaError(keywordPath, "invalid pattern: %v", err)
+		}
+	case "format":
+		format, ok := value.(string)
+		if !ok || formats[format] == nil {
+			return schemaError(keywordPath, "unsupported format %v", value)
+		}
+		s.format = format
+	case "minimum":
+		s.minimum, err = compileNumber(value, keywordPath)
+	case "maximum":
+		s.maximum, err = compileNumber(value, keywordPath)
+	case "exclusiveMinimum":
+		s.exclusiveMinimum, err = compileNumber(value, keywordPath)
+	case "exclusiveMaximum":
+		s.exclusiveMaximum, err = compileNumber(value, keywordPath)
+	case "multipleOf":
+		if s.multipleOf, err = compileNumber(value, keywordPath); err == nil && *s.multipleOf <= 0 {
+			return schemaError(keywordPath, "must be greater than 0")
+		}
+	case "items":
+		s.items, err = c.compile(value, keywordPath)
+	case "prefixItems":
+		s.prefixItems, err = c.compileList(value, keywordPath)
+	case "minItems":
+		s.minItems, err = compileCount(value, keywordPath)
+	case "maxItems":
+		s.maxItems, err = compileCount(value, keywordPath)
+	case "uniqueItems":
+		unique, ok := value.(bool)
+		if !ok {
+			return schemaError(keywordPath, "must be a boolean")
+		}
+		s.uniqueItems = unique
+	case "properties":
+		properties, ok := value.(map[string]interface{})
+		if !ok {
+			return schemaError(keywordPath, "must be an object")
+		}
+		s.properties = make(map[string]*Schema, len(properties))
+		for _, name := range sortedKeys(properties) {
+			if s.properties[name], err = c.compile(properties[name], keywordPath+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	case "required":
+		items, ok := value.([]interface{})
+		if !ok {
+			return schemaError(keywordPath, "must be an array of strings")
+		}
+		for _, item := range items {
+			name, ok := item.(string)
+			if !ok {
+				return schemaError(keywordPath, "must be an array of strings")
+			}
+			s.required = append(s.required, name)
+		}
+	case "additionalProperties":
+		s.additionalProperties, err = c.compile(value, keywordPath)
+	case "minProperties":
+		s.minProperties, err = compileCount(value, keywordPath)
+	case "maxProperties":
+		s.maxProperties, err = compileCount(value, keywordPath)
+	case "allOf":
+		s.allOf, err = c.compileList(value, keywordPath)
+	case "anyOf":
+		s.anyOf, err = c.compileList(value, keywordPath)
+	case "oneOf":
+		s.oneOf, err = c.compileList(value, keywordPath)
+	case "not":
+		s.not, err = c.compile(value, keywordPath)
+	case "$defs":
+		return schemaError(keywordPath, "definitions are only supported at the top level")
+	default:
+		if !annotations[key] {
+			return schemaError(keywordPath, "unsupported keyword")
+		}
+	}
+
+	return err
+}
+
+// compileList compiles a non-empty array of schemas
+func (c *compiler) compileList(value interface{}, path string) ([]*Schema, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, schemaError(path, "must be a non-empty array of schemas")
+	}
+
+	schemas := make([]*Schema, len(items))
+	for i, item := range items {
+		s, err := c.compile(item, fmt.Sprintf("%s/%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = s
+	}
+	return schemas, nil
+}
+
+// compileTypes compiles a type name or an array of type names
+func compileTypes(value interface{}, path string) ([]string, error) {
+	var names []interface{}
+	switch v := value.(type) {
+	case string:
+		names = []interface{}{v}
+	case []interface{}:
+		names = v
+	}
+	if len(names) == 0 {
+		return nil, schemaError(path, "must be a type name or an array of type names")
+	}
+
+	types := make([]string, len(names))
+	for i, name := range names {
+		typeName, ok := name.(string)
+		if !ok || !validTypes[typeName] {
+			return nil, schemaError(path, "invalid type %v", name)
+		}
+		types[i] = typeName
+	}
+	return types, nil
+}
+
+// compileNumber compiles a numeric keyword value
+func compileNumber(value interface{}, path string) (*float64, error) {
+	number, ok := toNumber(value)
+	if !ok {
+		return nil, schemaError(path, "must be a number")
+	}
+	return &number, nil
+}
+
+// compileCount compiles a non-negative integer keyword value
+func compileCount(value interface{}, path string) (*int, error) {
+	number, ok := toNumber(value)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, schemaError(path, "must be a non-negative integer")
+	}
+	count := int(number)
+	return &count, nil
+}
+
+// schemaError reports an invalid schema keyword
+func schemaError(path, format string, args ...interface{}) error {
+	if path == "" {
+		return fmt.Errorf("schema: "+format, args...)
+	}
+	return fmt.Errorf("schema "+path+": "+format, args...)
+}
+
+// Validate validates a value decoded from JSON, YAML or XML and returns every failure found
+func (s *Schema) Validate(value interface{}) []Error {
+	var errs []Error
+	s.validate(normalize(value), "", &errs)
+	return errs
+}
+
+// matches reports whether a value is valid without collecting failures
+func (s *Schema) matches(value interface{}) bool {
+	var errs []Error
+	s.validate(value, "", &errs)
+	return len(errs) == 0
+}
+
+// validate appends the failures of value, located at path, to errs
+func (s *Schema) validate(value interface{}, path string, errs *[]Error) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.reject {
+		fail("value is not allowed")
+		return
+	}
+	if s.ref != nil {
+		s.ref.validate(value, path, errs)
+	}
+
+	// Further failures of a value of the wrong type would only add noise
+	if len(s.types) > 0 && !hasType(value, s.types) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(value))
+		return
+	}
+
+	if s.enum != nil && !containsValue(s.enum, value) {
+		fail("must be one of %s", formatValue(s.enum))
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constValue, value) {
+		fail("must be %s", formatValue(s.constValue))
+	}
+
+	switch v := value.(type) {
+	case string:
+		s.validateString(v, fail)
+	case float64:
+		s.validateNumber(v, fail)
+	case []interface{}:
+		s.validateArray(v, path, errs, fail)
+	case map[string]interface{}:
+		s.validateObject(v, path, errs, fail)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(value, path, errs)
+	}
+	if s.anyOf != nil {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.matches(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must match at least one schema in anyOf")
+		}
+	}
+	if s.oneOf != nil {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if sub.matches(value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if s.not != nil && s.not.matches(value) {
+		fail("must not match the schema in not")
+	}
+}
+
+// validateString checks the string keywords
+func (s *Schema) validateString(value string, fail func(string, ...interface{})) {
+	length := utf8.RuneCountInString(value)
+	if s.minLength != nil && length < *s.minLength {
+		fail("must be at least %d characters long", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		fail("must be at most %d characters long", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		fail("must match pattern %s", s.pattern.String())
+	}
+	if s.format != "" && !ValidFormat(s.format, value) {
+		fail("must be a valid %s", s.format)
+	}
+}
+
+// validateNumber checks the numeric keywords
+func (s *Schema) validateNumber(value float64, fail func(string, ...interface{})) {
+	if s.minimum != nil && value < *s.minimum {
+		fail("must be greater than or equal to %s", formatValue(*s.minimum))
+	}
+	if s.maximum != nil && value > *s.maximum {
+		fail("must be less than or equal to %s", formatValue(*s.maximum))
+	}
+	if s.exclusiveMinimum != nil && value <= *s.exclusiveMinimum {
+		fail("must be greater than %s", formatValue(*s.exclusiveMinimum))
+	}
+	if s.exclusiveMaximum != nil && value >= *s.exclusiveMaximum {
+		fail("must be less than %s", formatValue(*s.exclusiveMaximum))
+	}
+	if s.multipleOf != nil {
+		quotient := value / *s.multipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("must be a multiple of %s", formatValue(*s.multipleOf))
+		}
+	}
+}
+
+// validateArray checks the array keywords and the items
+func (s *Schema) validateArray(items []interface{}, path string, errs *[]Error, fail func(string, ...interface{})) {
+	if s.minItems != nil && len(items) < *s.minItems {
+		fail("must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(items) > *s.maxItems {
+		fail("must have at most %d items", *s.maxItems)
+	}
+	if s.uniqueItems {
+	unique:
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if reflect.DeepEqual(items[i], items[j]) {
+					fail("items %d and %d must be unique", i, j)
+					break unique
+				}
+			}
+		}
+	}
+
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s/%d", path, i)
+		switch {
+		case i < len(s.prefixItems):
+			s.prefixItems[i].validate(item, itemPath, errs)
+		case s.items != nil:
+			s.items.validate(item, itemPath, errs)
+		}
+	}
+}
+
+// validateObject checks the object keywords and the properties
+func (s *Schema) validateObject(
+	object map[string]interface{},
+	path string,
+	errs *[]Error,
+	fail func(string, ...interface{}),
+) {
+	for _, name := range s.required {
+		if _, exists := object[name]; !exists {
+			fail("missing required property %q", name)
+		}
+	}
+	if s.minProperties != nil && len(object) < *s.minProperties {
+		fail("must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(object) > *s.maxProperties {
+		fail("must have at most %d properties", *s.maxProperties)
+	}
+
+	for _, name := range sortedKeys(object) {
+		if property, defined := s.properties[name]; defined {
+			property.validate(object[name], path+"/"+escapePointer(name), errs)
+			continue
+		}
+		if s.additionalProperties == nil {
+			continue
+		}
+		propertyPath := path + "/" + escapePointer(name)
+		if s.additionalProperties.reject {
+			*errs = append(*errs, Error{Path: propertyPath, Message: "property is not allowed"})
+			continue
+		}
+		s.additionalProperties.validate(object[name], propertyPath, errs)
+	}
+}
+
+// hasType reports whether a value has one of the types
+func hasType(value interface{}, types []string) bool {
+	actual := typeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a normalized value
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// normalize converts decoded numbers to float64 and generic maps to string-keyed maps,
+// so values decoded from YAML or XML compare equal to the same values decoded from JSON
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalize(item)
+		}
+		return normalized
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = normalize(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalize(item)
+		}
+		return normalized
+	default:
+		if number, ok := toNumber(value); ok {
+			return number
+		}
+		return value
+	}
+}
+
+// toNumber converts a decoded numeric value to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		number, err := v.Float64()
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue formats a schema value for a failure message
+func formatValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// escapePointer escapes a property name for use in a JSON Pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// compileYAML compiles a schema written in YAML, as it appears in the configuration
+func compileYAML(t *testing.T, document string) *Schema {
+	t.Helper()
+	var doc interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(document), &doc))
+	s, err := Compile(doc)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSchema_Validate(t *testing.T) {
+	order := compileYAML(t, `
+$defs:
+  item:
+    type: object
+    required: [sku, quantity]
+    properties:
+      sku: {type: string, pattern: "^[A-Z]{3}-[0-9]+$"}
+      quantity: {type: integer, minimum: 1, maximum: 10}
+type: object
+required: [customer, items]
+additionalProperties: false
+properties:
+  customer:
+    type: object
+    properties:
+      id: {type: string, format: uuid}
+      email: {type: string, format: email}
+  items:
+    type: array
+    minItems: 1
+    uniqueItems: true
+    items: {$ref: "#/$defs/item"}
+  channel: {enum: [web, mobile]}
+  note: {type: [string, "null"], maxLength: 5}
+  discount:
+    oneOf:
+      - {type: number, exclusiveMinimum: 0, exclusiveMaximum: 1}
+      - {const: 0}
+`)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:  "valid",
+			value: `{"customer": {"id": "0b8e2a8e-3c1f-4d1a-9e7b-2f0c5a6d7e8f"}, "items": [{"sku": "ABC-1", "quantity": 2}], "note": null, "discount": 0.5}`,
+		},
+		{
+			name:     "wrong root type",
+			value:    `[]`,
+			expected: []string{"expected object, got array"},
+		},
+		{
+			name:  "nested failures",
+			value: `{"customer": {"id": "nope", "email": "Ada <ada@example.com>"}, "items": [{"sku": "abc", "quantity": 1.5}, {"quantity": 11}], "channel": "fax", "note": "too long", "extra": 1, "discount": 2}`,
+			expected: []string{
+				"/channel: must be one of [\"web\",\"mobile\"]",
+				"/customer/email: must be a valid email",
+				"/customer/id: must be a valid uuid",
+				"/discount: must match exactly one schema in oneOf, matched 0",
+				"/extra: property is not allowed",
+				`/items/0/quantity: expected integer, got number`,
+				`/items/0/sku: must match pattern ^[A-Z]{3}-[0-9]+$`,
+				`/items/1: missing required property "sku"`,
+				`/items/1/quantity: must be less than or equal to 10`,
+				"/note: must be at most 5 characters long",
+			},
+		},
+		{
+			name:  "missing properties and empty array",
+			value: `{"items": []}`,
+			expected: []string{
+				`missing required property "customer"`,
+				"/items: must have at least 1 items",
+			},
+		},
+		{
+			name:     "duplicate items",
+			value:    `{"customer": {}, "items": [{"sku": "ABC-1", "quantity": 1}, {"sku": "ABC-1", "quantity": 1}]}`,
+			expected: []string{"/items: items 0 and 1 must be unique"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.value), &value))
+
+			var messages []string
+			for _, err := range order.Validate(value) {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func TestSchema_ValidateYAMLValues(t *testing.T) {
+	s := compileYAML(t, `{type: object, properties: {count: {type: integer, enum: [1, 2]}}}`)
+
+	// Values decoded from YAML use int rather than float64
+	assert.Empty(t, s.Validate(map[string]interface{}{"count": 2}))
+	assert.Len(t, s.Validate(map[string]interface{}{"count": 3}), 1)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		errorMsg string
+	}{
+		{name: "unsupported keyword", document: `{type: object, patternProperties: {}}`, errorMsg: "schema /patternProperties: unsupported keyword"},
+		{name: "invalid type", document: `{type: text}`, errorMsg: "schema /type: invalid type text"},
+		{name: "unknown reference", document: `{$ref: "#/$defs/missing"}`, errorMsg: "unsupported reference #/$defs/missing"},
+		{name: "remote reference", document: `{$ref: "https://example.com/schema.json"}`, errorMsg: "only #/$defs/<name> is supported"},
+		{name: "nested definitions", document: `{properties: {a: {$defs: {}}}}`, errorMsg: "schema /properties/a/$defs: definitions are only supported at the top level"},
+		{name: "invalid pattern", document: `{pattern: "("}`, errorMsg: "schema /pattern: invalid pattern"},
+		{name: "unsupported format", document: `{format: hostname}`, errorMsg: "schema /format: unsupported format hostname"},
+		{name: "negative count", document: `{minLength: -1}`, errorMsg: "schema /minLength: must be a non-negative integer"},
+		{name: "not a schema", document: `{items: 3}`, errorMsg: "schema /items: must be an object or a boolean"},
+		{name: "self reference", document: `{$defs: {A: {$ref: "#/$defs/A"}}, $ref: "#/$defs/A"}`, errorMsg: "schema /$defs/A: reference cycle"},
+		{name: "reference cycle", document: `{$defs: {A: {allOf: [{$ref: "#/$defs/B"}]}, B: {not: {$ref: "#/$defs/A"}}}}`, errorMsg: "schema /$defs/A: reference cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.document), &doc))
+			_, err := Compile(doc)
+			assert.ErrorContains(t, err, tt.errorMsg)
+		})
+	}
+}
+
+func TestSchema_RecursiveDefinition(t *testing.T) {
+	// A definition may refer to itself below a property or item
+	tree := compileYAML(t, `
+$defs:
+  node:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string}
+      children: {type: array, items: {$ref: "#/$defs/node"}}
+$ref: "#/$defs/node"
+`)
+
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "a", "children": [{"name": "b", "children": [{}]}]}`), &value))
+	errs := tree.Validate(value)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/children/0/children/0", errs[0].Path)
+}
+
+func TestValidFormat(t *testing.T) {
+	assert.True(t, ValidFormat("uuid", "0B8E2A8E-3C1F-4D1A-9E7B-2F0C5A6D7E8F"))
+	assert.False(t, ValidFormat("uuid", "0b8e2a8e3c1f4d1a9e7b2f0c5a6d7e8f"))
+	assert.True(t, ValidFormat("date-time", "2025-01-02T15:04:05Z"))
+	assert.False(t, ValidFormat("date", "2025-13-01"))
+	assert.True(t, ValidFormat("ipv6", "::1"))
+	assert.False(t, ValidFormat("ipv4", "::1"))
+	assert.False(t, ValidFormat("uri", "/relative"))
+	assert.False(t, ValidFormat("hostname", "example.com"))
+}
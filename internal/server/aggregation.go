@@ -4,7 +4,6 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,18 +11,20 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
-	"github.com/TrueTickets/api-aggregator/internal/client"
 	"github.com/TrueTickets/api-aggregator/internal/config"
 	"github.com/TrueTickets/api-aggregator/internal/types"
 )
 
 // createEndpointHandler creates a handler for a configured endpoint
 func (s *Server) createEndpointHandler(endpoint config.Endpoint) http.HandlerFunc {
+	runtime := s.newEndpointRuntime(endpoint)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -37,7 +38,17 @@ func (s *Server) createEndpointHandler(endpoint config.Endpoint) http.HandlerFun
 		for i, key := range routeCtx.URLParams.Keys {
 			pathParams[key] = routeCtx.URLParams.Values[i]
 		}
-		responses := s.aggregateBackends(timeoutCtx, endpoint, pathParams, r)
+
+		// Invalid requests are rejected before any backend is called
+		if runtime.validatorErr != nil {
+			s.writeErrorResponse(w, "Request validation unavailable")
+			return
+		}
+		if runtime.validator != nil && !s.validateRequest(w, r, runtime.validator, pathParams) {
+			return
+		}
+
+		responses := s.aggregateBackends(timeoutCtx, endpoint, runtime, pathParams, r)
 
 		// Handle case where all backends failed
 		if !s.hasSuccessfulResponse(responses) {
@@ -46,7 +57,7 @@ func (s *Server) createEndpointHandler(endpoint config.Endpoint) http.HandlerFun
 		}
 
 		// Merge responses and write success response
-		s.processMergedResponse(w, endpoint, responses)
+		s.processMergedResponse(ctx, w, endpoint, responses)
 	}
 }
 
@@ -73,18 +84,29 @@ func (s *Server) writeErrorResponse(w http.ResponseWriter, errorMsg string) {
 
 // processMergedResponse merges backend responses and writes the success response
 func (s *Server) processMergedResponse(
+	ctx context.Context,
 	w http.ResponseWriter,
 	endpoint config.Endpoint,
 	responses []types.BackendResponse,
 ) {
 	// Merge responses
-	mergedData, allCompleted := s.merger.Merge(responses)
+	mergedData, allCompleted, err := s.merger.MergeEndpoint(ctx, endpoint, responses)
+	if err != nil {
+		// The error names backend hosts and URL patterns, which are not for clients
+		s.logger.Warn().Err(err).Str("endpoint", endpoint.Endpoint).Msg("Failed to merge backend responses")
+		s.writeErrorResponse(w, "Merge conflict")
+		return
+	}
 
 	// Log aggregated response at trace level
 	s.logAggregatedResponse(endpoint, mergedData, allCompleted)
 
 	// Set response headers
+	s.setResponseHeaders(w.Header(), endpoint, responses, allCompleted)
 	w.Header().Set("X-API-Aggregation-Completed", fmt.Sprintf("%t", allCompleted))
+	if fallbacks := fallbackBackends(responses); len(fallbacks) > 0 {
+		w.Header().Set("X-API-Aggregation-Fallback", strings.Join(fallbacks, ","))
+	}
 	w.Header().Set("Content-Type", "application/json")
 
 	// Write response
@@ -112,6 +134,7 @@ func (s *Server) logAggregatedResponse(endpoint config.Endpoint, mergedData inte
 func (s *Server) aggregateBackends(
 	ctx context.Context,
 	endpoint config.Endpoint,
+	runtime *endpointRuntime,
 	pathParams map[string]string,
 	r *http.Request,
 ) []types.BackendResponse {
@@ -130,52 +153,99 @@ func (s *Server) aggregateBackends(
 		}
 	}
 
-	var wg sync.WaitGroup
+	in := &ingressRequest{
+		endpoint:   endpoint,
+		runtime:    runtime,
+		pathParams: pathParams,
+		request:    r,
+		body:       bodyBytes,
+	}
+
+	// Optional backends run under their own context so they can be cut off at the soft timeout
+	optionalCtx, cancelOptional := context.WithCancel(ctx)
+	defer cancelOptional()
+	hasRequired, hasOptional := s.backendRequirements(endpoint)
+
+	var wg, requiredWG sync.WaitGroup
 	responses := make([]types.BackendResponse, len(endpoint.Backends))
 
 	for i, backend := range endpoint.Backends {
+		backendCtx := ctx
+		isRequired := !backend.Optional || !hasRequired
+		if isRequired {
+			requiredWG.Add(1)
+		} else {
+			backendCtx = optionalCtx
+		}
+
 		wg.Add(1)
 		go func(idx int, be config.Backend) {
 			defer wg.Done()
-
-			// Build URL by replacing path parameters
-			url := s.buildURL(be, pathParams)
-
-			// Create body reader for each backend
-			var body io.Reader
-			if len(bodyBytes) > 0 {
-				body = bytes.NewReader(bodyBytes)
-			}
-
-			// Make request
-			data, err := s.client.Request(ctx, client.RequestConfig{
-				Method:   endpoint.Method,
-				URL:      url,
-				Encoding: be.Encoding,
-				Headers:  s.processHeaders(r, be.RemoveHeaders),
-				Body:     body,
-			})
-
-			responses[idx] = types.BackendResponse{
-				Backend: be,
-				Data:    data,
-				Error:   err,
+			if isRequired {
+				defer requiredWG.Done()
 			}
+			responses[idx] = s.callBackend(backendCtx, in, idx, be)
 		}(i, backend)
 	}
 
+	if hasRequired && hasOptional && endpoint.SoftTimeout > 0 {
+		s.waitForSoftTimeout(&wg, &requiredWG, endpoint.SoftTimeout, cancelOptional)
+	}
+
 	wg.Wait()
 	return responses
 }
 
+// backendRequirements reports whether an endpoint has required and optional backends
+func (s *Server) backendRequirements(endpoint config.Endpoint) (hasRequired, hasOptional bool) {
+	for _, backend := range endpoint.Backends {
+		if backend.Optional {
+			hasOptional = true
+		} else {
+			hasRequired = true
+		}
+	}
+	return hasRequired, hasOptional
+}
+
+// waitForSoftTimeout waits for all required backends, then gives optional backends the soft
+// timeout to finish before cancelling them
+func (s *Server) waitForSoftTimeout(
+	all, required *sync.WaitGroup,
+	softTimeout time.Duration,
+	cancelOptional context.CancelFunc,
+) {
+	allDone := make(chan struct{})
+	go func() {
+		all.Wait()
+		close(allDone)
+	}()
+
+	requiredDone := make(chan struct{})
+	go func() {
+		required.Wait()
+		close(requiredDone)
+	}()
+
+	select {
+	case <-allDone:
+		return
+	case <-requiredDone:
+	}
+
+	timer := time.NewTimer(softTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-allDone:
+	case <-timer.C:
+		cancelOptional()
+	}
+}
+
 // shouldForwardBody determines if request body should be forwarded based on HTTP method
 func (s *Server) shouldForwardBody(method string) bool {
-	switch method {
-	case http.MethodPost, http.MethodPut, http.MethodPatch:
-		return true
-	default:
-		return false
-	}
+	return config.AllowsRequestBody(method)
 }
 
 // buildURL builds the full URL for a backend request
@@ -198,18 +268,28 @@ func (s *Server) buildURL(backend config.Backend, pathParams map[string]string)
 	return baseURL + urlPattern
 }
 
-// processHeaders processes headers from the original request, forwarding all and removing specified ones
-func (s *Server) processHeaders(r *http.Request, removeHeaders []string) map[string]string {
-	headers := make(map[string]string)
+// processHeaders processes headers from the original request, forwarding all (or only the
+// allow-listed ones) and removing specified ones. All values of multi-valued headers are kept.
+func (s *Server) processHeaders(r *http.Request, forwardHeaders, removeHeaders []string) http.Header {
+	headers := make(http.Header)
 
-	// Create a set of headers to remove for efficient lookup
+	// Create sets of headers to forward and remove for efficient lookup
+	forwardHeadersSet := make(map[string]bool)
+	for _, header := range forwardHeaders {
+		forwardHeadersSet[strings.ToLower(header)] = true
+	}
 	removeHeadersSet := make(map[string]bool)
 	for _, header := range removeHeaders {
 		removeHeadersSet[strings.ToLower(header)] = true
 	}
 
-	// Forward all headers from the original request
+	// Forward headers from the original request
 	for name, values := range r.Header {
+		// Skip headers that are not allow-listed, if an allow-list is configured
+		if len(forwardHeadersSet) > 0 && !forwardHeadersSet[strings.ToLower(name)] {
+			continue
+		}
+
 		// Skip headers that should be removed
 		if removeHeadersSet[strings.ToLower(name)] {
 			continue
@@ -221,9 +301,8 @@ func (s *Server) processHeaders(r *http.Request, removeHeaders []string) map[str
 			continue
 		}
 
-		// Use the first value if multiple values exist
 		if len(values) > 0 {
-			headers[name] = values[0]
+			headers[name] = append([]string(nil), values...)
 		}
 	}
 
@@ -28,6 +28,7 @@ func TestServer_ProcessHeaders(t *testing.T) {
 	tests := []struct {
 		name           string
 		requestHeaders map[string]string
+		forwardHeaders []string
 		removeHeaders  []string
 		expected       map[string]string
 	}{
@@ -89,6 +90,20 @@ func TestServer_ProcessHeaders(t *testing.T) {
 				"Authorization": "Bearer token123",
 			},
 		},
+		{
+			name: "forward only allow-listed headers",
+			requestHeaders: map[string]string{
+				"Authorization": "Bearer token123",
+				"User-Agent":    "test-agent",
+				"X-Tenant":      "acme",
+				"Host":          "example.com",
+			},
+			forwardHeaders: []string{"x-tenant", "User-Agent", "Host"},
+			removeHeaders:  []string{"User-Agent"},
+			expected: map[string]string{
+				"X-Tenant": "acme",
+			},
+		},
 		{
 			name: "handle non-existent headers in remove list",
 			requestHeaders: map[string]string{
@@ -112,14 +127,31 @@ func TestServer_ProcessHeaders(t *testing.T) {
 			}
 
 			// Process headers
-			result := s.processHeaders(req, tt.removeHeaders)
+			result := s.processHeaders(req, tt.forwardHeaders, tt.removeHeaders)
 
 			// Verify result
-			assert.Equal(t, tt.expected, result)
+			expected := make(http.Header)
+			for key, value := range tt.expected {
+				expected.Set(key, value)
+			}
+			assert.Equal(t, expected, result)
 		})
 	}
 }
 
+func TestServer_ProcessHeaders_MultipleValues(t *testing.T) {
+	s := &Server{}
+
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Add("Accept-Language", "en")
+	req.Header.Add("Accept-Language", "de;q=0.8")
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+
+	result := s.processHeaders(req, nil, nil)
+	assert.Equal(t, []string{"en", "de;q=0.8"}, result.Values("Accept-Language"))
+	assert.Equal(t, []string{"10.0.0.1"}, result.Values("X-Forwarded-For"))
+}
+
 func TestServer_ShouldForwardBody(t *testing.T) {
 	s := &Server{}
 
@@ -350,6 +382,56 @@ func TestServer_ConcurrentBodyForwarding(t *testing.T) {
 	assert.NotNil(t, response)
 }
 
+func TestServer_BackendMethodOverride(t *testing.T) {
+	type received struct {
+		method      string
+		contentType string
+		body        string
+	}
+	requests := make(chan received, 2)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests <- received{method: r.Method, contentType: r.Header.Get("Content-Type"), body: string(body)}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{}`))
+		assert.NoError(t, err)
+	}
+
+	orders := httptest.NewServer(http.HandlerFunc(handler))
+	defer orders.Close()
+	cart := httptest.NewServer(http.HandlerFunc(handler))
+	defer cart.Close()
+
+	cfg := createTestConfig(http.MethodPost, orders.URL)
+	cfg.Endpoints[0].Backends = append(cfg.Endpoints[0].Backends, config.Backend{
+		Host:       cart.URL,
+		URLPattern: "/cart",
+		Encoding:   "json",
+		Method:     http.MethodGet,
+	})
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"item": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	close(requests)
+
+	var all []received
+	for r := range requests {
+		all = append(all, r)
+	}
+	assert.ElementsMatch(t, []received{
+		{method: http.MethodPost, contentType: "application/json", body: `{"item": 1}`},
+		{method: http.MethodGet},
+	}, all)
+}
+
 func TestServer_BodyForwardingWithLargeBody(t *testing.T) {
 	// Create a large body (1MB)
 	largeBody := strings.Repeat("x", 1024*1024)
@@ -528,3 +610,180 @@ func createTestServer(cfg *config.Config) *Server {
 		Logger: zerolog.Nop(),
 	})
 }
+
+func TestServer_OptionalBackendSoftDeadline(t *testing.T) {
+	requiredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"user": "John"}`))
+		require.NoError(t, err)
+	}))
+	defer requiredServer.Close()
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+			_, err := w.Write([]byte(`{"recommendations": []}`))
+			require.NoError(t, err)
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	cfg := &config.Config{
+		Endpoints: []config.Endpoint{
+			{
+				Endpoint:    "/test",
+				Method:      http.MethodGet,
+				Timeout:     5 * time.Second,
+				SoftTimeout: 50 * time.Millisecond,
+				Encoding:    "json",
+				Backends: []config.Backend{
+					{Host: requiredServer.URL, URLPattern: "/test", Encoding: "json"},
+					{Host: slowServer.URL, URLPattern: "/test", Encoding: "json", Optional: true},
+				},
+			},
+		},
+	}
+	server := createTestServer(cfg)
+
+	start := time.Now()
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "false", w.Header().Get("X-API-Aggregation-Completed"))
+	assert.JSONEq(t, `{"user": "John"}`, w.Body.String())
+}
+
+func TestServer_MergeConflictHidesBackends(t *testing.T) {
+	usersServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer usersServer.Close()
+
+	accountsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"id": 2}`))
+		require.NoError(t, err)
+	}))
+	defer accountsServer.Close()
+
+	cfg := &config.Config{
+		Endpoints: []config.Endpoint{
+			{
+				Endpoint: "/test",
+				Method:   http.MethodGet,
+				Timeout:  5 * time.Second,
+				Encoding: "json",
+				Merge:    config.MergeOptions{MergePolicy: config.MergePolicy{Strategy: "error"}},
+				Backends: []config.Backend{
+					{Host: usersServer.URL, URLPattern: "/internal/users", Encoding: "json"},
+					{Host: accountsServer.URL, URLPattern: "/internal/accounts", Encoding: "json"},
+				},
+			},
+		},
+	}
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "Merge conflict"}`, w.Body.String())
+}
+
+func TestServer_SoftTimeoutStartsAfterRequiredBackends(t *testing.T) {
+	tests := []struct {
+		name        string
+		softTimeout time.Duration
+	}{
+		{name: "grace after slow required backend", softTimeout: 150 * time.Millisecond},
+		{name: "no soft timeout waits for the endpoint timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requiredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(150 * time.Millisecond)
+				_, err := w.Write([]byte(`{"user": "John"}`))
+				require.NoError(t, err)
+			}))
+			defer requiredServer.Close()
+
+			optionalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				_, err := w.Write([]byte(`{"score": 5}`))
+				require.NoError(t, err)
+			}))
+			defer optionalServer.Close()
+
+			cfg := &config.Config{
+				Endpoints: []config.Endpoint{
+					{
+						Endpoint:    "/test",
+						Method:      http.MethodGet,
+						Timeout:     5 * time.Second,
+						SoftTimeout: tt.softTimeout,
+						Encoding:    "json",
+						Backends: []config.Backend{
+							{Host: requiredServer.URL, URLPattern: "/test", Encoding: "json"},
+							{Host: optionalServer.URL, URLPattern: "/test", Encoding: "json", Optional: true},
+						},
+					},
+				},
+			}
+			server := createTestServer(cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			// The optional backend finishes 50ms after the required one, within the grace period
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "true", w.Header().Get("X-API-Aggregation-Completed"))
+			assert.JSONEq(t, `{"user": "John", "score": 5}`, w.Body.String())
+		})
+	}
+}
+
+func TestServer_OptionalBackendWithinSoftDeadline(t *testing.T) {
+	requiredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"user": "John"}`))
+		require.NoError(t, err)
+	}))
+	defer requiredServer.Close()
+
+	optionalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, err := w.Write([]byte(`{"score": 5}`))
+		require.NoError(t, err)
+	}))
+	defer optionalServer.Close()
+
+	cfg := &config.Config{
+		Endpoints: []config.Endpoint{
+			{
+				Endpoint:    "/test",
+				Method:      http.MethodGet,
+				Timeout:     5 * time.Second,
+				SoftTimeout: time.Second,
+				Encoding:    "json",
+				Backends: []config.Backend{
+					{Host: requiredServer.URL, URLPattern: "/test", Encoding: "json"},
+					{Host: optionalServer.URL, URLPattern: "/test", Encoding: "json", Optional: true},
+				},
+			},
+		},
+	}
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-API-Aggregation-Completed"))
+	assert.JSONEq(t, `{"user": "John", "score": 5}`, w.Body.String())
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/TrueTickets/api-aggregator/internal/auth"
+	"github.com/TrueTickets/api-aggregator/internal/client"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/schema"
+	"github.com/TrueTickets/api-aggregator/internal/types"
+)
+
+// endpointRuntime holds per-endpoint state that lives as long as the server
+type endpointRuntime struct {
+	// hedgers holds the hedging state for each backend, nil if hedging is disabled
+	hedgers []*hedger
+
+	// authenticators holds the credentials provider for each backend, nil if auth is not configured
+	authenticators []client.Authenticator
+
+	// responseSchemas holds the compiled response schema for each backend, nil if not validated
+	responseSchemas []*schema.Schema
+
+	// validator checks requests before backends are called, nil if the endpoint validates nothing
+	validator *requestValidator
+
+	// validatorErr is set if the validator could not be created; requests are then rejected
+	validatorErr error
+}
+
+// newEndpointRuntime creates the runtime state for an endpoint
+func (s *Server) newEndpointRuntime(endpoint config.Endpoint) *endpointRuntime {
+	runtime := &endpointRuntime{
+		hedgers:         make([]*hedger, len(endpoint.Backends)),
+		authenticators:  make([]client.Authenticator, len(endpoint.Backends)),
+		responseSchemas: make([]*schema.Schema, len(endpoint.Backends)),
+	}
+
+	for j, backend := range endpoint.Backends {
+		if backend.Hedge != nil {
+			runtime.hedgers[j] = newHedger(*backend.Hedge, backend.Host)
+		}
+		if backend.Auth != nil {
+			runtime.authenticators[j] = s.newAuthenticator(endpoint, backend)
+		}
+		if backend.ResponseSchemaMode != "off" {
+			runtime.responseSchemas[j] = s.newResponseSchema(endpoint, backend)
+		}
+	}
+
+	runtime.validator, runtime.validatorErr = newRequestValidator(endpoint)
+	if runtime.validatorErr != nil {
+		s.logger.Error().
+			Err(runtime.validatorErr).
+			Str("endpoint", endpoint.Endpoint).
+			Msg("Failed to configure request validation")
+	}
+
+	return runtime
+}
+
+// newAuthenticator creates the authenticator for a backend. Secrets are checked when
+// the configuration is loaded, so a failure here means they changed since; requests to the
+// backend then fail rather than being sent without credentials.
+func (s *Server) newAuthenticator(endpoint config.Endpoint, backend config.Backend) client.Authenticator {
+	authCfg := auth.Config{Auth: *backend.Auth}
+	if backend.Auth.Type == "oauth2" {
+		// Tokens are requested with the backend's transport settings, e.g. its client certificate
+		tokenBackend := backend
+		tokenBackend.Host = backend.Auth.TokenURL
+		transport, err := s.transports.forBackend(tokenBackend)
+		if err != nil {
+			return s.failedAuthenticator(endpoint, backend, err)
+		}
+		authCfg.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	authenticator, err := auth.New(authCfg)
+	if err != nil {
+		return s.failedAuthenticator(endpoint, backend, err)
+	}
+	return authenticator
+}
+
+// failedAuthenticator logs a credentials error and returns an authenticator failing every request
+func (s *Server) failedAuthenticator(endpoint config.Endpoint, backend config.Backend, err error) client.Authenticator {
+	s.logger.Error().
+		Err(err).
+		Str("endpoint", endpoint.Endpoint).
+		Str("backend", backend.Label()).
+		Msg("Failed to configure backend auth")
+	return &failedAuth{err: err, header: backend.Auth.Header}
+}
+
+// failedAuth rejects requests to a backend whose credentials could not be loaded
+type failedAuth struct {
+	err    error
+	header string
+}
+
+// Authenticate returns the credentials error
+func (f *failedAuth) Authenticate(context.Context, *http.Request, []byte) error {
+	return f.err
+}
+
+// Header returns the credential header name
+func (f *failedAuth) Header() string {
+	return f.header
+}
+
+// ingressRequest holds the parts of the incoming request shared by all backend requests
+type ingressRequest struct {
+	endpoint   config.Endpoint
+	runtime    *endpointRuntime
+	pathParams map[string]string
+	request    *http.Request
+	body       []byte
+
+	// Caller JWT claims for header templates, decoded on first use
+	claimsOnce sync.Once
+	claims     map[string]interface{}
+}
+
+// backendMethod returns the HTTP method for a backend request, the endpoint method unless overridden
+func (in *ingressRequest) backendMethod(backend config.Backend) string {
+	if backend.Method != "" {
+		return backend.Method
+	}
+	return in.endpoint.Method
+}
+
+// newResponseSchema compiles the response schema of a backend, nil if it has none. Schemas
+// are checked when the configuration is loaded, so a failure here means a schema file changed
+// since; responses are then not validated rather than failing every request.
+func (s *Server) newResponseSchema(endpoint config.Endpoint, backend config.Backend) *schema.Schema {
+	document, err := backend.LoadResponseSchema()
+	if err == nil && document != nil {
+		var compiled *schema.Schema
+		if compiled, err = schema.Compile(document); err == nil {
+			return compiled
+		}
+	}
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("endpoint", endpoint.Endpoint).
+			Str("backend", backend.Label()).
+			Msg("Failed to load backend response schema")
+	}
+	return nil
+}
+
+// callBackend requests a backend, trying its fallbacks in order if it fails.
+// A fallback response is attributed to the primary backend so it is merged the same way.
+func (s *Server) callBackend(
+	ctx context.Context,
+	in *ingressRequest,
+	idx int,
+	backend config.Backend,
+) types.BackendResponse {
+	var resp *client.Response
+	var err error
+	if h := in.runtime.hedgers[idx]; h != nil && config.IsIdempotentMethod(in.backendMethod(backend)) {
+		resp, err = s.requestHedged(ctx, in, idx, backend, h)
+	} else {
+		resp, err = s.requestBackend(ctx, in, idx, backend)
+	}
+	if err == nil {
+		return types.BackendResponse{Backend: backend, Data: resp.Data, Header: resp.Header}
+	}
+
+	for i := range backend.Fallback {
+		// No point trying fallbacks once the endpoint deadline has passed
+		if ctx.Err() != nil {
+			break
+		}
+
+		fallback := &backend.Fallback[i]
+		s.logger.Warn().
+			Err(err).
+			Str("backend", backend.Label()).
+			Str("fallback", fallback.Host).
+			Msg("Backend request failed, trying fallback")
+
+		resp, err = s.requestBackend(ctx, in, idx, fallbackTarget(backend, *fallback))
+		if err == nil {
+			return types.BackendResponse{Backend: backend, Data: resp.Data, Header: resp.Header, Fallback: fallback}
+		}
+	}
+
+	return types.BackendResponse{Backend: backend, Error: err}
+}
+
+// requestBackend makes a single request to the backend at idx, or to a fallback or hedge target for it
+func (s *Server) requestBackend(
+	ctx context.Context,
+	in *ingressRequest,
+	idx int,
+	backend config.Backend,
+) (*client.Response, error) {
+	// Apply the per-backend timeout within the endpoint deadline
+	if backend.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, backend.Timeout)
+		defer cancel()
+	}
+
+	transport, err := s.transports.forBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build URL by replacing path parameters
+	url := s.buildURL(backend, in.pathParams)
+
+	// Create body reader for each backend
+	method := in.backendMethod(backend)
+	headers := s.backendHeaders(in, backend)
+	var body io.Reader
+	switch {
+	case !config.AllowsRequestBody(method):
+		// The ingress body is only sent with methods that carry one, and so is its Content-Type
+		headers.Del("Content-Type")
+	case backend.Body != nil:
+		shaped, err := s.backendBody(ctx, in, backend)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(shaped)
+
+		// The forwarded Content-Type describes the ingress body
+		headers.Del("Content-Type")
+	case len(in.body) > 0:
+		body = bytes.NewReader(in.body)
+	}
+
+	resp, err := s.client.Do(ctx, client.RequestConfig{
+		Method:                method,
+		URL:                   url,
+		Encoding:              backend.Encoding,
+		Headers:               headers,
+		Body:                  body,
+		Transport:             transport,
+		Auth:                  in.runtime.authenticators[idx],
+		ResponseSchema:        in.runtime.responseSchemas[idx],
+		EnforceResponseSchema: backend.ResponseSchemaMode != "warn",
+	})
+
+	var schemaErr *client.SchemaError
+	if errors.As(err, &schemaErr) || (err == nil && len(resp.SchemaErrors) > 0) {
+		s.recordSchemaMismatch(ctx, backend)
+	}
+	return resp, err
+}
+
+// recordSchemaMismatch records a backend response not matching its schema in metrics
+func (s *Server) recordSchemaMismatch(ctx context.Context, backend config.Backend) {
+	mode := backend.ResponseSchemaMode
+	if mode == "" {
+		mode = "enforce"
+	}
+	s.schemaMismatchCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("backend", backend.Label()),
+		attribute.String("mode", mode),
+	))
+}
+
+// fallbackTarget returns the backend configuration used to request a fallback
+func fallbackTarget(backend config.Backend, fallback config.Fallback) config.Backend {
+	target := backend
+	target.Host = fallback.Host
+	target.URLPattern = fallback.URLPattern
+	target.Encoding = fallback.Encoding
+	return target
+}
+
+// fallbackBackends returns the labels of backends whose response came from a fallback
+func fallbackBackends(responses []types.BackendResponse) []string {
+	var labels []string
+	for _, resp := range responses {
+		if resp.Fallback != nil {
+			labels = append(labels, resp.Backend.Label())
+		}
+	}
+	return labels
+}
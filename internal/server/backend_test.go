@@ -0,0 +1,322 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestServer_Fallback(t *testing.T) {
+	var primaryCalls, fallbackCalls int32
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+		assert.Equal(t, "/archive/users/1", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"id": 1, "name": "John"}}`))
+		require.NoError(t, err)
+	}))
+	defer fallbackServer.Close()
+
+	cfg := &config.Config{
+		Endpoints: []config.Endpoint{
+			{
+				Endpoint: "/users/{id}",
+				Method:   http.MethodGet,
+				Timeout:  5 * time.Second,
+				Encoding: "json",
+				Backends: []config.Backend{
+					{
+						Name:       "users",
+						Host:       failingServer.URL,
+						URLPattern: "/users/{id}",
+						Encoding:   "json",
+						Group:      "user",
+						Target:     "data",
+						Fallback: []config.Fallback{
+							{Host: failingServer.URL, URLPattern: "/users/{id}", Encoding: "json"},
+							{Host: fallbackServer.URL, URLPattern: "/archive/users/{id}", Encoding: "json"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-API-Aggregation-Completed"))
+	assert.Equal(t, "users", w.Header().Get("X-API-Aggregation-Fallback"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&primaryCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fallbackCalls))
+
+	// The fallback response uses the primary backend's group and target
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{"id": float64(1), "name": "John"},
+	}, response)
+}
+
+func TestServer_FallbackNotUsedOnSuccess(t *testing.T) {
+	var fallbackCalls int32
+
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer primaryServer.Close()
+
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+	}))
+	defer fallbackServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, primaryServer.URL)
+	cfg.Endpoints[0].Backends[0].Fallback = []config.Fallback{
+		{Host: fallbackServer.URL, URLPattern: "/test", Encoding: "json"},
+	}
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-API-Aggregation-Fallback"))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fallbackCalls))
+}
+
+func TestServer_BackendTimeoutTriggersFallback(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"source": "fallback"}`))
+		require.NoError(t, err)
+	}))
+	defer fallbackServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, slowServer.URL)
+	cfg.Endpoints[0].Backends[0].Timeout = 50 * time.Millisecond
+	cfg.Endpoints[0].Backends[0].Fallback = []config.Fallback{
+		{Host: fallbackServer.URL, URLPattern: "/test", Encoding: "json"},
+	}
+	server := createTestServer(cfg)
+
+	start := time.Now()
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"source": "fallback"}`, w.Body.String())
+}
+
+func TestServer_BackendAuth(t *testing.T) {
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Backend credentials replace the caller's
+		assert.Equal(t, "Bearer backend-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer authServer.Close()
+
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer caller-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"name": "John"}`))
+		require.NoError(t, err)
+	}))
+	defer plainServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, authServer.URL)
+	cfg.Endpoints[0].Backends[0].Auth = &config.Auth{
+		Type:  "bearer",
+		Token: config.Secret{Value: "backend-token"},
+	}
+	cfg.Endpoints[0].Backends = append(cfg.Endpoints[0].Backends, config.Backend{
+		Host:       plainServer.URL,
+		URLPattern: "/test",
+		Encoding:   "json",
+	})
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer caller-token")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-API-Aggregation-Completed"))
+}
+
+func TestServer_OAuth2UsesBackendTransport(t *testing.T) {
+	// The token endpoint has a self-signed certificate only the backend TLS settings accept
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"access_token": "oauth-token", "expires_in": 3600}`))
+		require.NoError(t, err)
+	}))
+	defer tokenServer.Close()
+
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer oauth-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer backendServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, backendServer.URL)
+	cfg.Endpoints[0].Backends[0].TLS = &config.TLS{InsecureSkipVerify: true}
+	cfg.Endpoints[0].Backends[0].Auth = &config.Auth{
+		Type:         "oauth2",
+		TokenURL:     tokenServer.URL + "/token",
+		ClientID:     "aggregator",
+		ClientSecret: config.Secret{Value: "s3cret"},
+	}
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id": 1}`, w.Body.String())
+}
+
+// modeCounter records the mode attribute of each schema mismatch metric
+type modeCounter struct {
+	metricnoop.Int64Counter
+	mu    sync.Mutex
+	modes []string
+}
+
+func (c *modeCounter) Add(_ context.Context, _ int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	mode, _ := attrs.Value("mode")
+	c.mu.Lock()
+	c.modes = append(c.modes, mode.AsString())
+	c.mu.Unlock()
+}
+
+func TestServer_ResponseSchema(t *testing.T) {
+	usersServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"user": {"id": "7"}}`))
+		require.NoError(t, err)
+	}))
+	defer usersServer.Close()
+
+	ordersServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"orders": "none"}`))
+		require.NoError(t, err)
+	}))
+	defer ordersServer.Close()
+
+	userSchema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"user"},
+		"properties": map[string]interface{}{
+			"user": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}},
+			},
+		},
+	}
+	ordersSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"orders": map[string]interface{}{"type": "array"}},
+	}
+
+	tests := []struct {
+		name              string
+		usersMode         string
+		ordersMode        string
+		expectedCompleted string
+		expectedBody      string
+		expectedModes     []string
+	}{
+		{
+			name:              "enforce fails the backend",
+			usersMode:         "enforce",
+			ordersMode:        "warn",
+			expectedCompleted: "false",
+			expectedBody:      `{"orders": "none"}`,
+			expectedModes:     []string{"enforce", "warn"},
+		},
+		{
+			name:              "warn keeps the response",
+			usersMode:         "warn",
+			ordersMode:        "off",
+			expectedCompleted: "true",
+			expectedBody:      `{"user": {"id": "7"}, "orders": "none"}`,
+			expectedModes:     []string{"warn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(http.MethodGet, usersServer.URL)
+			cfg.Endpoints[0].Backends[0].ResponseSchema = userSchema
+			cfg.Endpoints[0].Backends[0].ResponseSchemaMode = tt.usersMode
+			cfg.Endpoints[0].Backends = append(cfg.Endpoints[0].Backends, config.Backend{
+				Host:               ordersServer.URL,
+				URLPattern:         "/orders",
+				Encoding:           "json",
+				ResponseSchema:     ordersSchema,
+				ResponseSchemaMode: tt.ordersMode,
+			})
+
+			server := createTestServer(cfg)
+			counter := &modeCounter{}
+			server.schemaMismatchCounter = counter
+
+			req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedCompleted, w.Header().Get("X-API-Aggregation-Completed"))
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.ElementsMatch(t, tt.expectedModes, counter.modes)
+		})
+	}
+}
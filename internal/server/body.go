@@ -0,0 +1,112 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/TrueTickets/api-aggregator/internal/client"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// backendBody shapes the JSON ingress body for a backend: the target subtree is selected,
+// fields are filtered and renamed, set fields are injected, and the result is encoded in
+// the backend encoding. The body is decoded per backend so shaping never affects another.
+func (s *Server) backendBody(ctx context.Context, in *ingressRequest, backend config.Backend) ([]byte, error) {
+	_, span := s.tracer.Start(ctx, "transform_request_body")
+	defer span.End()
+
+	shape := backend.Body
+
+	var data interface{} = map[string]interface{}{}
+	if len(in.body) > 0 {
+		if err := decodeJSONNumbers(in.body, &data); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+
+	if shape.Target != "" {
+		data = s.transformer.ApplyTarget(data, shape.Target)
+	}
+	if len(shape.Allow) > 0 || len(shape.Deny) > 0 {
+		data = s.transformer.ApplyFiltering(data, shape.Allow, shape.Deny)
+	}
+	if len(shape.Mapping) > 0 {
+		data = s.transformer.ApplyMapping(data, shape.Mapping)
+	}
+
+	if len(shape.Set) > 0 {
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid request body: set requires a JSON object")
+		}
+
+		// Shorter paths are set first so nested paths are set inside them
+		paths := make([]string, 0, len(shape.Set))
+		for path := range shape.Set {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			value := shape.Set[path]
+			if template, isTemplate := value.(string); isTemplate {
+				value = s.expandTemplate(in, template)
+			} else {
+				value = cloneValue(value)
+			}
+			s.transformer.SetNestedField(object, path, value)
+		}
+	}
+
+	body, err := client.EncodeBody(data, backend.Encoding, shape.Root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	return body, nil
+}
+
+// decodeJSONNumbers decodes a JSON document keeping numbers as json.Number, so they are
+// encoded again as they were in any encoding
+func decodeJSONNumbers(body []byte, data *interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(data); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid data after the JSON document")
+	}
+	return nil
+}
+
+// cloneValue copies maps and slices of a configured constant so setting nested
+// fields in one request body never modifies the configuration
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return value
+	}
+}
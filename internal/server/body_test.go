@@ -0,0 +1,168 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestServer_BackendBody(t *testing.T) {
+	s := createTestServer(createTestConfig(http.MethodPost, "http://backend"))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/7?region=eu", http.NoBody)
+	in := &ingressRequest{
+		request:    req,
+		pathParams: map[string]string{"id": "7"},
+		body: []byte(`{"user": {"name": "Ada", "email": "ada@example.com", "password": "secret",` +
+			` "id": 9007199254740993, "quota": 1000000}, "trace": true}`),
+	}
+
+	tests := []struct {
+		name     string
+		backend  config.Backend
+		expected string
+	}{
+		{
+			name: "target, filter and map",
+			backend: config.Backend{
+				Encoding: "json",
+				Body: &config.RequestBody{
+					Target:  "user",
+					Deny:    []string{"password", "id", "quota"},
+					Mapping: map[string]string{"email": "contact.email"},
+				},
+			},
+			expected: `{"contact":{"email":"ada@example.com"},"name":"Ada"}`,
+		},
+		{
+			name: "set templates and constants",
+			backend: config.Backend{
+				Encoding: "json",
+				Body: &config.RequestBody{
+					Allow: []string{"trace"},
+					Set: map[string]interface{}{
+						"id":            "{path.id}",
+						"meta":          map[string]interface{}{"source": "aggregator"},
+						"meta.region":   "{query.region}",
+						"meta.priority": 3,
+					},
+				},
+			},
+			expected: `{"id":"7","meta":{"priority":3,"region":"eu","source":"aggregator"},"trace":true}`,
+		},
+		{
+			name: "json in, xml out",
+			backend: config.Backend{
+				Encoding: "xml",
+				Body: &config.RequestBody{
+					Target: "user",
+					Allow:  []string{"name"},
+					Set:    map[string]interface{}{"id": "{path.id}"},
+					Root:   "createUser",
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<createUser><id>7</id><name>Ada</name></createUser>`,
+		},
+		{
+			name: "large numbers in xml",
+			backend: config.Backend{
+				Encoding: "xml",
+				Body:     &config.RequestBody{Target: "user", Allow: []string{"id", "quota"}, Root: "user"},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<user><id>9007199254740993</id><quota>1000000</quota></user>`,
+		},
+		{
+			name: "large numbers in yaml",
+			backend: config.Backend{
+				Encoding: "yaml",
+				Body:     &config.RequestBody{Target: "user", Allow: []string{"id", "quota"}},
+			},
+			expected: "id: 9007199254740993\nquota: 1000000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := s.backendBody(context.Background(), in, tt.backend)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+
+	// Configured constants are never modified by nested set paths
+	meta := tests[1].backend.Body.Set["meta"]
+	assert.Equal(t, map[string]interface{}{"source": "aggregator"}, meta)
+}
+
+func TestServer_BackendBody_Invalid(t *testing.T) {
+	s := createTestServer(createTestConfig(http.MethodPost, "http://backend"))
+	req := httptest.NewRequest(http.MethodPost, "/test", http.NoBody)
+	backend := config.Backend{Encoding: "json", Body: &config.RequestBody{Set: map[string]interface{}{"id": 1}}}
+
+	_, err := s.backendBody(context.Background(), &ingressRequest{request: req, body: []byte(`not json`)}, backend)
+	assert.ErrorContains(t, err, "invalid request body")
+
+	_, err = s.backendBody(context.Background(), &ingressRequest{request: req, body: []byte(`{} {}`)}, backend)
+	assert.ErrorContains(t, err, "invalid request body: invalid data after the JSON document")
+
+	_, err = s.backendBody(context.Background(), &ingressRequest{request: req, body: []byte(`[1, 2]`)}, backend)
+	assert.ErrorContains(t, err, "invalid request body: set requires a JSON object")
+}
+
+func TestServer_BackendBodyPerBackend(t *testing.T) {
+	received := make(chan string, 2)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received <- r.Header.Get("Content-Type") + " " + string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{}`))
+		assert.NoError(t, err)
+	}
+
+	shaped := httptest.NewServer(http.HandlerFunc(handler))
+	defer shaped.Close()
+	unchanged := httptest.NewServer(http.HandlerFunc(handler))
+	defer unchanged.Close()
+
+	cfg := createTestConfig(http.MethodPost, shaped.URL)
+	cfg.Endpoints[0].Backends[0].ForwardHeaders = []string{"Content-Type"}
+	cfg.Endpoints[0].Backends[0].Body = &config.RequestBody{Allow: []string{"name"}}
+	cfg.Endpoints[0].Backends = append(cfg.Endpoints[0].Backends, config.Backend{
+		Host:       unchanged.URL,
+		URLPattern: "/test",
+		Encoding:   "json",
+	})
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name": "Ada", "age": 36}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	close(received)
+
+	var bodies []string
+	for body := range received {
+		bodies = append(bodies, body)
+	}
+	assert.ElementsMatch(t, []string{
+		`application/json {"name":"Ada"}`,
+		`application/json; charset=utf-8 {"name": "Ada", "age": 36}`,
+	}, bodies)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// backendHeaders builds the headers for a backend request: forwarded headers are
+// renamed, then set_headers replace and add_headers append templated values
+func (s *Server) backendHeaders(in *ingressRequest, backend config.Backend) http.Header {
+	headers := s.processHeaders(in.request, backend.ForwardHeaders, backend.RemoveHeaders)
+
+	for from, to := range backend.RenameHeaders {
+		values := headers.Values(from)
+		if len(values) == 0 {
+			continue
+		}
+		headers.Del(from)
+		for _, value := range values {
+			headers.Add(to, value)
+		}
+	}
+
+	// Headers whose template expands to an empty value are not sent
+	for name, template := range backend.SetHeaders {
+		headers.Del(name)
+		if value := s.expandTemplate(in, template); value != "" {
+			headers.Set(name, value)
+		}
+	}
+	for name, template := range backend.AddHeaders {
+		if value := s.expandTemplate(in, template); value != "" {
+			headers.Add(name, value)
+		}
+	}
+
+	return headers
+}
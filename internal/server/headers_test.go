@@ -0,0 +1,90 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestServer_BackendHeaders(t *testing.T) {
+	s := &Server{}
+
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"sub":"user-42","org":{"id":"acme"},"roles":["admin","dev"],"iat":1700000000}`))
+	token := "eyJhbGciOiJIUzI1NiJ9." + payload + ".signature"
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7?region=eu", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Add("X-Legacy-Tenant", "acme")
+	req.Header.Add("X-Legacy-Tenant", "acme-eu")
+	req.Header.Set("X-Trace", "abc")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "req-1"))
+
+	in := &ingressRequest{
+		request:    req,
+		pathParams: map[string]string{"id": "7"},
+	}
+
+	tests := []struct {
+		name     string
+		backend  config.Backend
+		expected http.Header
+	}{
+		{
+			name: "rename keeps all values",
+			backend: config.Backend{
+				ForwardHeaders: []string{"X-Legacy-Tenant"},
+				RenameHeaders:  map[string]string{"X-Legacy-Tenant": "X-Tenant"},
+			},
+			expected: http.Header{"X-Tenant": {"acme", "acme-eu"}},
+		},
+		{
+			name: "set and add templated headers",
+			backend: config.Backend{
+				ForwardHeaders: []string{"X-Trace"},
+				SetHeaders: map[string]string{
+					"X-Trace":      "{header.X-Trace}-{request_id}",
+					"X-User":       "{claims.sub}@{claims.org.id}",
+					"X-Resource":   "users/{path.id}?region={query.region}",
+					"X-Missing":    "{query.missing}",
+					"X-Issued-At":  "{claims.iat}",
+					"X-User-Roles": "{claims.roles}",
+				},
+				AddHeaders: map[string]string{"X-Trace": "static"},
+			},
+			expected: http.Header{
+				"X-Trace":      {"abc-req-1", "static"},
+				"X-User":       {"user-42@acme"},
+				"X-Resource":   {"users/7?region=eu"},
+				"X-Issued-At":  {"1700000000"},
+				"X-User-Roles": {"admin,dev"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.backendHeaders(in, tt.backend))
+		})
+	}
+}
+
+func TestDecodeBearerClaims(t *testing.T) {
+	assert.Nil(t, decodeBearerClaims(""))
+	assert.Nil(t, decodeBearerClaims("Basic dXNlcjpwYXNz"))
+	assert.Nil(t, decodeBearerClaims("Bearer opaque-token"))
+	assert.Nil(t, decodeBearerClaims("Bearer a.not-base64!.c"))
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-42"}`))
+	assert.Equal(t, map[string]interface{}{"sub": "user-42"}, decodeBearerClaims("Bearer a."+payload+".c"))
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/TrueTickets/api-aggregator/internal/client"
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+const (
+	// Number of latency samples kept for the adaptive hedge delay
+	hedgeLatencySamples = 200
+	// Minimum number of samples before the adaptive delay replaces the configured delay
+	hedgeMinSamples = 20
+	// Percentile of observed latency used as the adaptive delay
+	hedgePercentile = 0.95
+)
+
+// Hedge outcomes recorded in metrics
+const (
+	hedgeOutcomePrimaryWon  = "primary_won"
+	hedgeOutcomeHedgeWon    = "hedge_won"
+	hedgeOutcomeRateLimited = "rate_limited"
+	hedgeOutcomeFailed      = "failed"
+)
+
+// hedger holds the hedging state for a single backend
+type hedger struct {
+	cfg   config.Hedge
+	hosts []string
+
+	mu          sync.Mutex
+	next        int
+	latencies   []time.Duration
+	latencyPos  int
+	windowStart time.Time
+	windowCount int
+}
+
+// hedgeResult is the outcome of a single attempt in a hedged request
+type hedgeResult struct {
+	resp     *client.Response
+	err      error
+	duration time.Duration
+	hedge    bool
+}
+
+// newHedger creates hedging state for a backend
+func newHedger(cfg config.Hedge, defaultHost string) *hedger {
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{defaultHost}
+	}
+
+	return &hedger{
+		cfg:       cfg,
+		hosts:     hosts,
+		latencies: make([]time.Duration, 0, hedgeLatencySamples),
+	}
+}
+
+// delay returns the time to wait before issuing a hedged request
+func (h *hedger) delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.cfg.Adaptive || len(h.latencies) < hedgeMinSamples {
+		return h.cfg.Delay
+	}
+
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted[int(float64(len(sorted)-1)*hedgePercentile)]
+}
+
+// observe records the latency of a primary request. Hedged attempts are not sampled: they
+// start late and would bias the percentile, and with it the delay, downwards.
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < hedgeLatencySamples {
+		h.latencies = append(h.latencies, latency)
+		return
+	}
+	h.latencies[h.latencyPos] = latency
+	h.latencyPos = (h.latencyPos + 1) % hedgeLatencySamples
+}
+
+// allow reports whether another hedged request may be issued within the per-second cap
+func (h *hedger) allow(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if now.Sub(h.windowStart) >= time.Second {
+		h.windowStart = now
+		h.windowCount = 0
+	}
+	if h.windowCount >= h.cfg.MaxPerSecond {
+		return false
+	}
+	h.windowCount++
+	return true
+}
+
+// nextHost returns the host for the next hedged request, rotating through the configured hosts
+func (h *hedger) nextHost() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	host := h.hosts[h.next%len(h.hosts)]
+	h.next++
+	return host
+}
+
+// requestHedged requests a backend and, if it has not responded after the hedge delay,
+// issues a duplicate request to another host. The first successful response wins and
+// the other request is cancelled.
+func (s *Server) requestHedged(
+	ctx context.Context,
+	in *ingressRequest,
+	idx int,
+	backend config.Backend,
+	h *hedger,
+) (*client.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Cancels the losing request
+
+	// Buffered so the losing attempt never blocks after we return
+	results := make(chan hedgeResult, 2)
+	attempt := func(target config.Backend, hedge bool) {
+		start := time.Now()
+		resp, err := s.requestBackend(ctx, in, idx, target)
+		results <- hedgeResult{resp: resp, err: err, duration: time.Since(start), hedge: hedge}
+	}
+
+	primaryStart := time.Now()
+	go attempt(backend, false)
+
+	timer := time.NewTimer(h.delay())
+	defer timer.Stop()
+
+	select {
+	case res := <-results:
+		// The primary finished before the hedge delay
+		if res.err == nil {
+			h.observe(res.duration)
+		}
+		return res.resp, res.err
+	case <-timer.C:
+	}
+
+	if !h.allow(time.Now()) {
+		s.recordHedge(ctx, backend, hedgeOutcomeRateLimited)
+		res := <-results
+		if res.err == nil {
+			h.observe(res.duration)
+		}
+		return res.resp, res.err
+	}
+
+	hedgeTarget := backend
+	hedgeTarget.Host = h.nextHost()
+	go attempt(hedgeTarget, true)
+
+	// Use the first successful response; fail only if both attempts fail
+	var lastErr error
+	primaryDone := false
+	for i := 0; i < 2; i++ {
+		res := <-results
+		if !res.hedge {
+			primaryDone = true
+		}
+		if res.err != nil {
+			lastErr = res.err
+			continue
+		}
+
+		outcome := hedgeOutcomePrimaryWon
+		if res.hedge {
+			outcome = hedgeOutcomeHedgeWon
+			// The primary is cancelled, so its latency is at least the time it has run so far
+			if !primaryDone {
+				h.observe(time.Since(primaryStart))
+			}
+		} else {
+			h.observe(res.duration)
+		}
+		s.recordHedge(ctx, backend, outcome)
+		return res.resp, nil
+	}
+
+	s.recordHedge(ctx, backend, hedgeOutcomeFailed)
+	return nil, lastErr
+}
+
+// recordHedge records a hedge outcome in metrics
+func (s *Server) recordHedge(ctx context.Context, backend config.Backend, outcome string) {
+	s.hedgeCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("backend", backend.Label()),
+		attribute.String("outcome", outcome),
+	))
+}
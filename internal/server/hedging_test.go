@@ -0,0 +1,220 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// recordingCounter records the outcome attribute of each hedge metric
+type recordingCounter struct {
+	metricnoop.Int64Counter
+	mu       sync.Mutex
+	outcomes []string
+}
+
+func (c *recordingCounter) Add(_ context.Context, _ int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	outcome, _ := attrs.Value("outcome")
+	c.mu.Lock()
+	c.outcomes = append(c.outcomes, outcome.AsString())
+	c.mu.Unlock()
+}
+
+func TestServer_HedgedRequest(t *testing.T) {
+	slowCancelled := make(chan struct{}, 1)
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+			_, err := w.Write([]byte(`{"source": "slow"}`))
+			require.NoError(t, err)
+		case <-r.Context().Done():
+			slowCancelled <- struct{}{}
+		}
+	}))
+	defer slowServer.Close()
+
+	fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"source": "fast"}`))
+		require.NoError(t, err)
+	}))
+	defer fastServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, slowServer.URL)
+	cfg.Endpoints[0].Backends[0].Hedge = &config.Hedge{
+		Delay:        20 * time.Millisecond,
+		Hosts:        []string{fastServer.URL},
+		MaxPerSecond: 10,
+	}
+	server := createTestServer(cfg)
+	counter := &recordingCounter{}
+	server.hedgeCounter = counter
+
+	start := time.Now()
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"source": "fast"}`, w.Body.String())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{hedgeOutcomeHedgeWon}, counter.outcomes)
+
+	// The losing request is cancelled
+	select {
+	case <-slowCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("slow request was not cancelled")
+	}
+}
+
+func TestServer_HedgeNotSentForFastPrimary(t *testing.T) {
+	var mu sync.Mutex
+	hedgeCalls := 0
+
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"source": "primary"}`))
+		require.NoError(t, err)
+	}))
+	defer primaryServer.Close()
+
+	hedgeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hedgeCalls++
+		mu.Unlock()
+	}))
+	defer hedgeServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, primaryServer.URL)
+	cfg.Endpoints[0].Backends[0].Hedge = &config.Hedge{
+		Delay:        time.Second,
+		Hosts:        []string{hedgeServer.URL},
+		MaxPerSecond: 10,
+	}
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"source": "primary"}`, w.Body.String())
+	mu.Lock()
+	assert.Equal(t, 0, hedgeCalls)
+	mu.Unlock()
+}
+
+func TestServer_HedgeBothFail(t *testing.T) {
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primaryServer.Close()
+
+	hedgeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hedgeServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, primaryServer.URL)
+	cfg.Endpoints[0].Backends[0].Hedge = &config.Hedge{
+		Delay:        20 * time.Millisecond,
+		Hosts:        []string{hedgeServer.URL},
+		MaxPerSecond: 10,
+	}
+	server := createTestServer(cfg)
+	counter := &recordingCounter{}
+	server.hedgeCounter = counter
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, []string{hedgeOutcomeFailed}, counter.outcomes)
+}
+
+func TestServer_HedgeLatencySamples(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	hedgeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		_, err := w.Write([]byte(`{"source": "hedge"}`))
+		require.NoError(t, err)
+	}))
+	defer hedgeServer.Close()
+
+	cfg := createTestConfig(http.MethodGet, slowServer.URL)
+	delay := 20 * time.Millisecond
+	cfg.Endpoints[0].Backends[0].Hedge = &config.Hedge{
+		Delay:        delay,
+		Hosts:        []string{hedgeServer.URL},
+		MaxPerSecond: 10,
+	}
+	server := createTestServer(cfg)
+	endpoint := cfg.Endpoints[0]
+	in := &ingressRequest{
+		endpoint:   endpoint,
+		runtime:    server.newEndpointRuntime(endpoint),
+		pathParams: map[string]string{},
+		request:    httptest.NewRequest(http.MethodGet, "/test", http.NoBody),
+	}
+	h := in.runtime.hedgers[0]
+
+	_, err := server.requestHedged(context.Background(), in, 0, endpoint.Backends[0], h)
+	require.NoError(t, err)
+
+	// The losing primary is sampled with the time it ran, not the hedge's shorter duration
+	require.Len(t, h.latencies, 1)
+	assert.GreaterOrEqual(t, h.latencies[0], delay+30*time.Millisecond)
+}
+
+func TestHedger_Delay(t *testing.T) {
+	h := newHedger(config.Hedge{Delay: 50 * time.Millisecond, Adaptive: true, MaxPerSecond: 1}, "http://a")
+
+	// Configured delay is used until enough samples are observed
+	assert.Equal(t, 50*time.Millisecond, h.delay())
+
+	for i := 1; i <= 100; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, 95*time.Millisecond, h.delay())
+}
+
+func TestHedger_Allow(t *testing.T) {
+	h := newHedger(config.Hedge{MaxPerSecond: 2}, "http://a")
+	now := time.Now()
+
+	assert.True(t, h.allow(now))
+	assert.True(t, h.allow(now))
+	assert.False(t, h.allow(now.Add(500*time.Millisecond)))
+	assert.True(t, h.allow(now.Add(time.Second)))
+}
+
+func TestHedger_NextHost(t *testing.T) {
+	h := newHedger(config.Hedge{Hosts: []string{"http://a", "http://b"}}, "http://primary")
+	assert.Equal(t, "http://a", h.nextHost())
+	assert.Equal(t, "http://b", h.nextHost())
+	assert.Equal(t, "http://a", h.nextHost())
+
+	h = newHedger(config.Hedge{}, "http://primary")
+	assert.Equal(t, "http://primary", h.nextHost())
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TrueTickets/api-aggregator/internal/openapi"
+)
+
+// openAPIHandler serves the OpenAPI document, generated once from the configuration
+func (s *Server) openAPIHandler() http.HandlerFunc {
+	doc, err := openapi.Generate(s.config)
+	var body []byte
+	if err == nil {
+		body, err = json.Marshal(doc)
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to generate OpenAPI document")
+		return func(w http.ResponseWriter, _ *http.Request) {
+			s.writeErrorResponse(w, "OpenAPI document unavailable")
+		}
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(body); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to write OpenAPI document")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestServer_OpenAPI(t *testing.T) {
+	tests := []struct {
+		name           string
+		openAPI        config.OpenAPI
+		target         string
+		expectedStatus int
+	}{
+		{
+			name:           "disabled",
+			openAPI:        config.OpenAPI{Path: "/openapi.json"},
+			target:         "/openapi.json",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "enabled",
+			openAPI:        config.OpenAPI{Enabled: true, Path: "/openapi.json", Title: "Test API"},
+			target:         "/openapi.json",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "custom path",
+			openAPI:        config.OpenAPI{Enabled: true, Path: "/docs/openapi.json", Title: "Test API"},
+			target:         "/docs/openapi.json",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(http.MethodGet, "http://backend")
+			cfg.OpenAPI = tt.openAPI
+			server := createTestServer(cfg)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var doc struct {
+				OpenAPI string `json:"openapi"`
+				Info    struct {
+					Title string `json:"title"`
+				} `json:"info"`
+				Paths map[string]map[string]interface{} `json:"paths"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			assert.Equal(t, "3.1.0", doc.OpenAPI)
+			assert.Equal(t, "Test API", doc.Info.Title)
+			assert.Contains(t, doc.Paths["/test"], "get")
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/types"
+)
+
+// exposedHeader collects the values of a response header from each backend exposing it
+type exposedHeader struct {
+	// Merge policy of the first backend exposing the header
+	merge string
+
+	// Values per backend, in backend order
+	values [][]string
+}
+
+// setResponseHeaders sets the backend response headers exposed by the endpoint's backends,
+// merged across backends, followed by the endpoint's static response headers.
+// A backend exposing Cache-Control without sending it gives no caching permission, and
+// partial responses are never cacheable, whatever the backends or static headers allow.
+func (s *Server) setResponseHeaders(
+	header http.Header,
+	endpoint config.Endpoint,
+	responses []types.BackendResponse,
+	allCompleted bool,
+) {
+	exposed := make(map[string]*exposedHeader)
+	var names []string
+
+	for _, resp := range responses {
+		if resp.Error != nil || resp.Header == nil {
+			continue
+		}
+		for _, rule := range resp.Backend.ExposeHeaders {
+			add := func(target string, values []string) {
+				if rule.As != "" {
+					target = http.CanonicalHeaderKey(rule.As)
+				}
+				entry, exists := exposed[target]
+				if !exists {
+					entry = &exposedHeader{merge: rule.Merge}
+					exposed[target] = entry
+					names = append(names, target)
+				}
+				entry.values = append(entry.values, values)
+			}
+
+			matches := matchingHeaders(resp.Header, rule.Name)
+			if len(matches) == 0 && rule.Merge == "cache_control" && !strings.HasSuffix(rule.Name, "*") {
+				add(http.CanonicalHeaderKey(rule.Name), []string{"no-store"})
+			}
+			for _, name := range matches {
+				add(name, resp.Header.Values(name))
+			}
+		}
+	}
+
+	for _, name := range names {
+		entry := exposed[name]
+		header.Del(name)
+		for _, value := range mergeHeaderValues(entry.merge, entry.values) {
+			if value != "" {
+				header.Add(name, value)
+			}
+		}
+	}
+
+	for name, value := range endpoint.ResponseHeaders {
+		header.Set(name, value)
+	}
+
+	if !allCompleted {
+		header.Set("Cache-Control", "no-store")
+	}
+}
+
+// matchingHeaders returns the canonical names of the response headers matching a name,
+// where a trailing * matches by prefix. Headers set by the service are never matched.
+func matchingHeaders(header http.Header, pattern string) []string {
+	prefix, isPrefix := strings.CutSuffix(pattern, "*")
+	if !isPrefix {
+		name := http.CanonicalHeaderKey(pattern)
+		if len(header.Values(name)) == 0 {
+			return nil
+		}
+		return []string{name}
+	}
+
+	var names []string
+	for name := range header {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) &&
+			!config.IsControlledResponseHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergeHeaderValues combines the values of a header from several backends
+func mergeHeaderValues(merge string, values [][]string) []string {
+	switch merge {
+	case "last":
+		return values[len(values)-1]
+	case "append":
+		var all []string
+		for _, backendValues := range values {
+			all = append(all, backendValues...)
+		}
+		return all
+	case "min", "max":
+		return mergeNumericValues(merge == "min", values)
+	case "cache_control":
+		return []string{mergeCacheControl(values)}
+	default:
+		return values[0]
+	}
+}
+
+// mergeNumericValues returns the lowest or highest numeric value, or the first
+// backend's values if no value is numeric
+func mergeNumericValues(lowest bool, values [][]string) []string {
+	var result float64
+	found := false
+
+	for _, backendValues := range values {
+		for _, value := range backendValues {
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			if !found || (lowest && number < result) || (!lowest && number > result) {
+				result = number
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return values[0]
+	}
+	return []string{strconv.FormatFloat(result, 'f', -1, 64)}
+}
+
+// mergeCacheControl combines Cache-Control values into the most restrictive policy:
+// no-store wins, ages take the minimum, and immutable is kept only if all backends agree
+func mergeCacheControl(values [][]string) string {
+	var noStore, noCache, private, public, mustRevalidate, proxyRevalidate, noTransform bool
+	immutable := 0
+	maxAge, sharedMaxAge := -1, -1
+
+	minAge := func(current int, value string) int {
+		age, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil || age < 0 {
+			return current
+		}
+		if current < 0 || age < current {
+			return age
+		}
+		return current
+	}
+
+	for _, backendValues := range values {
+		backendImmutable := false
+		for _, directive := range strings.Split(strings.Join(backendValues, ","), ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(name) {
+			case "no-store":
+				noStore = true
+			case "no-cache":
+				noCache = true
+			case "private":
+				private = true
+			case "public":
+				public = true
+			case "must-revalidate":
+				mustRevalidate = true
+			case "proxy-revalidate":
+				proxyRevalidate = true
+			case "no-transform":
+				noTransform = true
+			case "immutable":
+				backendImmutable = true
+			case "max-age":
+				maxAge = minAge(maxAge, value)
+			case "s-maxage":
+				sharedMaxAge = minAge(sharedMaxAge, value)
+			}
+		}
+		if backendImmutable {
+			immutable++
+		}
+	}
+
+	if noStore {
+		return "no-store"
+	}
+
+	var directives []string
+	switch {
+	case private:
+		directives = append(directives, "private")
+	case public:
+		directives = append(directives, "public")
+	}
+	if noCache {
+		directives = append(directives, "no-cache")
+	}
+	if maxAge >= 0 {
+		directives = append(directives, "max-age="+strconv.Itoa(maxAge))
+	}
+	if sharedMaxAge >= 0 && !private {
+		directives = append(directives, "s-maxage="+strconv.Itoa(sharedMaxAge))
+	}
+	if mustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if proxyRevalidate && !private {
+		directives = append(directives, "proxy-revalidate")
+	}
+	if noTransform {
+		directives = append(directives, "no-transform")
+	}
+	if immutable == len(values) {
+		directives = append(directives, "immutable")
+	}
+
+	return strings.Join(directives, ", ")
+}
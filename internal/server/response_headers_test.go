@@ -0,0 +1,170 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/types"
+)
+
+func TestServer_SetResponseHeaders(t *testing.T) {
+	s := &Server{}
+
+	users := config.Backend{ExposeHeaders: []config.ExposeHeader{
+		{Name: "Cache-Control", Merge: "cache_control"},
+		{Name: "Set-Cookie", Merge: "append"},
+		{Name: "X-RateLimit-*", Merge: "min"},
+		{Name: "ETag", As: "X-Users-ETag", Merge: "first"},
+	}}
+	orders := config.Backend{ExposeHeaders: []config.ExposeHeader{
+		{Name: "cache-control", Merge: "cache_control"},
+		{Name: "Set-Cookie", Merge: "append"},
+		{Name: "X-RateLimit-*", Merge: "min"},
+	}}
+
+	responses := []types.BackendResponse{
+		{
+			Backend: users,
+			Header: http.Header{
+				"Cache-Control":         {"public, max-age=300, s-maxage=600"},
+				"Set-Cookie":            {"a=1"},
+				"X-Ratelimit-Remaining": {"40"},
+				"Etag":                  {`"v1"`},
+				"Content-Type":          {"application/json"},
+			},
+		},
+		{
+			Backend: orders,
+			Header: http.Header{
+				"Cache-Control":         {"private, max-age=60"},
+				"Set-Cookie":            {"b=2", "c=3"},
+				"X-Ratelimit-Remaining": {"12"},
+				"X-Ratelimit-Limit":     {"100"},
+			},
+		},
+		{
+			Backend: users,
+			Error:   errors.New("ignored"),
+			Header:  http.Header{"Set-Cookie": {"ignored=1"}},
+		},
+	}
+
+	endpoint := config.Endpoint{ResponseHeaders: map[string]string{"X-Service": "aggregator"}}
+
+	header := make(http.Header)
+	s.setResponseHeaders(header, endpoint, responses, true)
+
+	assert.Equal(t, "private, max-age=60", header.Get("Cache-Control"))
+	assert.Equal(t, []string{"a=1", "b=2", "c=3"}, header.Values("Set-Cookie"))
+	assert.Equal(t, "12", header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "100", header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, `"v1"`, header.Get("X-Users-ETag"))
+	assert.Empty(t, header.Get("ETag"))
+	assert.Empty(t, header.Get("Content-Type"))
+	assert.Equal(t, "aggregator", header.Get("X-Service"))
+
+	// Partial responses are not cacheable, even without exposed or with static Cache-Control
+	header = make(http.Header)
+	s.setResponseHeaders(header, endpoint, responses, false)
+	assert.Equal(t, "no-store", header.Get("Cache-Control"))
+
+	header = make(http.Header)
+	static := config.Endpoint{ResponseHeaders: map[string]string{"Cache-Control": "public, max-age=60"}}
+	s.setResponseHeaders(header, static, []types.BackendResponse{{Backend: config.Backend{}}}, false)
+	assert.Equal(t, "no-store", header.Get("Cache-Control"))
+
+	// A backend exposing Cache-Control without sending it gives no caching permission
+	header = make(http.Header)
+	s.setResponseHeaders(header, endpoint, []types.BackendResponse{
+		responses[0],
+		{Backend: orders, Header: http.Header{"Set-Cookie": {"b=2"}}},
+	}, true)
+	assert.Equal(t, "no-store", header.Get("Cache-Control"))
+}
+
+func TestMergeCacheControl(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   [][]string
+		expected string
+	}{
+		{
+			name:     "minimum ages",
+			values:   [][]string{{"public, max-age=300, s-maxage=600"}, {"public, max-age=120", "s-maxage=900"}},
+			expected: "public, max-age=120, s-maxage=600",
+		},
+		{
+			name:     "no-store wins",
+			values:   [][]string{{"public, max-age=300"}, {"no-store"}},
+			expected: "no-store",
+		},
+		{
+			name:     "private drops shared directives",
+			values:   [][]string{{"public, s-maxage=600, proxy-revalidate"}, {"private, no-cache, must-revalidate"}},
+			expected: "private, no-cache, must-revalidate",
+		},
+		{
+			name:     "immutable only if all backends agree",
+			values:   [][]string{{"max-age=31536000, immutable"}, {"max-age=86400"}},
+			expected: "max-age=86400",
+		},
+		{
+			name:     "immutable kept",
+			values:   [][]string{{"max-age=31536000, immutable"}, {"max-age=\"86400\", immutable"}},
+			expected: "max-age=86400, immutable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeCacheControl(tt.values))
+		})
+	}
+}
+
+func TestMergeHeaderValues(t *testing.T) {
+	values := [][]string{{"10"}, {"3.5"}, {"n/a"}}
+
+	assert.Equal(t, []string{"10"}, mergeHeaderValues("first", values))
+	assert.Equal(t, []string{"n/a"}, mergeHeaderValues("last", values))
+	assert.Equal(t, []string{"10", "3.5", "n/a"}, mergeHeaderValues("append", values))
+	assert.Equal(t, []string{"3.5"}, mergeHeaderValues("min", values))
+	assert.Equal(t, []string{"10"}, mergeHeaderValues("max", values))
+	assert.Equal(t, []string{"n/a"}, mergeHeaderValues("min", [][]string{{"n/a"}}))
+}
+
+func TestServer_ExposeHeaders(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("X-Internal", "secret")
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer backend.Close()
+
+	cfg := createTestConfig(http.MethodGet, backend.URL)
+	cfg.Endpoints[0].Backends[0].ExposeHeaders = []config.ExposeHeader{{Name: "Cache-Control", Merge: "cache_control"}}
+	cfg.Endpoints[0].ResponseHeaders = map[string]string{"X-Frame-Options": "DENY"}
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Empty(t, w.Header().Get("X-Internal"))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
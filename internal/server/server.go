@@ -6,35 +6,41 @@ package server
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/TrueTickets/api-aggregator/internal/client"
 	"github.com/TrueTickets/api-aggregator/internal/config"
 	"github.com/TrueTickets/api-aggregator/internal/merger"
+	"github.com/TrueTickets/api-aggregator/internal/transformer"
 )
 
 const (
-	defaultHTTPTimeoutSeconds = 30
 	// Minimum size for compression (1KB)
 	compressionMinSize = 1024
 )
 
 // Server represents the API aggregation server
 type Server struct {
-	config *config.Config
-	router *chi.Mux
-	client *client.Client
-	merger *merger.Merger
-	tracer trace.Tracer
-	meter  metric.Meter
-	logger zerolog.Logger
+	config      *config.Config
+	router      *chi.Mux
+	client      *client.Client
+	transports  *transportPool
+	merger      *merger.Merger
+	transformer *transformer.Transformer
+	tracer      trace.Tracer
+	meter       metric.Meter
+	logger      zerolog.Logger
+
+	// Metrics
+	hedgeCounter          metric.Int64Counter
+	schemaMismatchCounter metric.Int64Counter
 }
 
 // Config holds server configuration
@@ -54,10 +60,16 @@ func New(cfg Config) *Server {
 		logger: cfg.Logger,
 	}
 
-	// Create HTTP client
-	httpClient := &http.Client{
-		Timeout: defaultHTTPTimeoutSeconds * time.Second,
+	// Use a no-op meter if none is provided
+	if s.meter == nil {
+		s.meter = metricnoop.NewMeterProvider().Meter("api-aggregator")
 	}
+	s.setupMetrics()
+
+	// Create HTTP client; request deadlines come from endpoint and backend timeouts
+	httpClient := &http.Client{}
+	s.transports = newTransportPool(s.config)
+	s.warnInsecureTLS()
 	s.client = client.New(client.Config{
 		HTTPClient: httpClient,
 		Tracer:     s.tracer,
@@ -69,12 +81,61 @@ func New(cfg Config) *Server {
 		Tracer: s.tracer,
 	})
 
+	// Create transformer for backend request bodies
+	s.transformer = transformer.New(transformer.Config{
+		Tracer: s.tracer,
+	})
+
 	// Setup routes
 	s.setupRoutes()
 
 	return s
 }
 
+// setupMetrics creates the server metric instruments
+func (s *Server) setupMetrics() {
+	var err error
+	s.hedgeCounter, err = s.meter.Int64Counter(
+		"api_aggregator.backend.hedges",
+		metric.WithDescription("Hedged backend requests by outcome"),
+	)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to create hedge counter")
+		s.hedgeCounter, _ = metricnoop.NewMeterProvider().Meter("api-aggregator").Int64Counter(
+			"api_aggregator.backend.hedges")
+	}
+
+	s.schemaMismatchCounter, err = s.meter.Int64Counter(
+		"api_aggregator.backend.schema_mismatches",
+		metric.WithDescription("Backend responses not matching their response schema, by mode"),
+	)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to create schema mismatch counter")
+		s.schemaMismatchCounter, _ = metricnoop.NewMeterProvider().Meter("api-aggregator").Int64Counter(
+			"api_aggregator.backend.schema_mismatches")
+	}
+}
+
+// warnInsecureTLS logs a warning for each backend that skips TLS certificate verification
+func (s *Server) warnInsecureTLS() {
+	for _, endpoint := range s.config.Endpoints {
+		for _, backend := range endpoint.Backends {
+			transport := s.config.TransportFor(backend)
+			if transport.TLS != nil && transport.TLS.InsecureSkipVerify {
+				s.logger.Warn().
+					Str("endpoint", endpoint.Endpoint).
+					Str("backend", backend.Label()).
+					Msg("TLS certificate verification is disabled for backend")
+			}
+		}
+	}
+}
+
+// Close releases idle backend connections held by the server
+func (s *Server) Close() {
+	s.transports.closeIdleConnections()
+}
+
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
@@ -87,6 +148,7 @@ func (s *Server) setupRoutes() {
 	// Add middleware
 	s.router.Use(
 		middleware.Recoverer,
+		middleware.RequestID,
 		s.compressionMiddleware,
 		s.loggingMiddleware,
 		s.tracingMiddleware,
@@ -99,6 +161,10 @@ func (s *Server) setupRoutes() {
 	s.router.Get("/livez", s.handleLiveness)
 	s.router.Get("/readyz", s.handleReadiness)
 
+	if s.config.OpenAPI.Enabled {
+		s.router.Get(s.config.OpenAPI.Path, s.openAPIHandler())
+	}
+
 	// Add configured endpoints
 	for _, endpoint := range s.config.Endpoints {
 		handler := s.createEndpointHandler(endpoint)
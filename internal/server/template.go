@@ -0,0 +1,103 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// expandTemplate replaces placeholders with values from the incoming request.
+// Missing values expand to an empty string.
+func (s *Server) expandTemplate(in *ingressRequest, template string) string {
+	return config.TemplatePattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		match := config.TemplatePattern.FindStringSubmatch(placeholder)
+		source, name := match[1], match[2]
+
+		switch source {
+		case "path":
+			return in.pathParams[name]
+		case "query":
+			return in.request.URL.Query().Get(name)
+		case "header":
+			return in.request.Header.Get(name)
+		case "claims":
+			return claimValue(in.callerClaims(), name)
+		case "request_id":
+			return middleware.GetReqID(in.request.Context())
+		default:
+			return ""
+		}
+	})
+}
+
+// callerClaims returns the claims of the caller's bearer JWT. The signature is not
+// verified: claims are forwarded as context and backends must not trust them for authorization.
+func (in *ingressRequest) callerClaims() map[string]interface{} {
+	in.claimsOnce.Do(func() {
+		in.claims = decodeBearerClaims(in.request.Header.Get("Authorization"))
+	})
+	return in.claims
+}
+
+// decodeBearerClaims decodes the payload of a bearer JWT, returning nil if it is not one
+func decodeBearerClaims(authorization string) map[string]interface{} {
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found {
+		return nil
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// claimValue formats a claim at a dot-notation path as a header value
+func claimValue(claims map[string]interface{}, path string) string {
+	var value interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[part]
+	}
+
+	switch v := value.(type) {
+	case nil, map[string]interface{}:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	case float64:
+		// Print integral numbers such as timestamps without an exponent
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// transportKey identifies backends that can share an HTTP transport
+type transportKey struct {
+	maxIdleConns          int
+	maxIdleConnsPerHost   int
+	maxConnsPerHost       int
+	idleConnTimeout       time.Duration
+	keepAlive             time.Duration
+	disableKeepAlives     bool
+	disableHTTP2          bool
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	disableCompression    bool
+	tls                   config.TLS
+	hasTLS                bool
+}
+
+// transportPool holds the HTTP transports for backends, shared between backends with the
+// same effective settings so that connections to the same host are pooled together
+type transportPool struct {
+	config *config.Config
+
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
+}
+
+// newTransportPool creates an empty transport pool for the configuration
+func newTransportPool(cfg *config.Config) *transportPool {
+	return &transportPool{
+		config:     cfg,
+		transports: make(map[transportKey]*http.Transport),
+	}
+}
+
+// forBackend returns the transport for a backend, creating it on first use.
+// Certificates are loaded when the transport is created, so a new pool picks up rotated files.
+func (p *transportPool) forBackend(backend config.Backend) (http.RoundTripper, error) {
+	key := newTransportKey(p.config.TransportFor(backend))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	transport, exists := p.transports[key]
+	if !exists {
+		var err error
+		transport, err = newTransport(key)
+		if err != nil {
+			return nil, err
+		}
+		p.transports[key] = transport
+	}
+	return transport, nil
+}
+
+// closeIdleConnections closes idle connections on all transports
+func (p *transportPool) closeIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, transport := range p.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+// newTransportKey resolves transport settings into a comparable key
+func newTransportKey(t config.Transport) transportKey {
+	key := transportKey{
+		maxIdleConns:          t.MaxIdleConns,
+		maxIdleConnsPerHost:   t.MaxIdleConnsPerHost,
+		maxConnsPerHost:       t.MaxConnsPerHost,
+		idleConnTimeout:       t.IdleConnTimeout,
+		keepAlive:             t.KeepAlive,
+		disableKeepAlives:     t.DisableKeepAlives != nil && *t.DisableKeepAlives,
+		disableHTTP2:          t.HTTP2 != nil && !*t.HTTP2,
+		dialTimeout:           t.DialTimeout,
+		tlsHandshakeTimeout:   t.TLSHandshakeTimeout,
+		responseHeaderTimeout: t.ResponseHeaderTimeout,
+		disableCompression:    t.DisableCompression != nil && *t.DisableCompression,
+	}
+	if t.TLS != nil {
+		key.tls = *t.TLS
+		key.hasTLS = true
+	}
+	return key
+}
+
+// newTransport creates an HTTP transport from the default transport with the given settings
+func newTransport(key transportKey) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	dialer := &net.Dialer{
+		Timeout:   key.dialTimeout,
+		KeepAlive: key.keepAlive,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.MaxIdleConns = key.maxIdleConns
+	transport.MaxIdleConnsPerHost = key.maxIdleConnsPerHost
+	transport.MaxConnsPerHost = key.maxConnsPerHost
+	transport.IdleConnTimeout = key.idleConnTimeout
+	transport.DisableKeepAlives = key.disableKeepAlives
+	transport.TLSHandshakeTimeout = key.tlsHandshakeTimeout
+	transport.ResponseHeaderTimeout = key.responseHeaderTimeout
+	transport.DisableCompression = key.disableCompression
+
+	if key.hasTLS {
+		tlsConfig, err := key.tls.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure backend TLS: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if key.disableHTTP2 {
+		// A non-nil empty map disables the automatic HTTP/2 upgrade
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return transport, nil
+}
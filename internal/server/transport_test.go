@@ -0,0 +1,138 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/testcert"
+)
+
+func TestTransportPool(t *testing.T) {
+	disabled := false
+	cfg := &config.Config{
+		Transport: config.Transport{
+			MaxIdleConns:        256,
+			MaxIdleConnsPerHost: 64,
+			IdleConnTimeout:     90 * time.Second,
+			DialTimeout:         30 * time.Second,
+		},
+		HostTransports: map[string]config.Transport{
+			"http://users.example.com": {MaxConnsPerHost: 10, HTTP2: &disabled},
+		},
+	}
+
+	users := config.Backend{Host: "http://users.example.com", URLPattern: "/users"}
+	usersStats := config.Backend{Host: "http://users.example.com/", URLPattern: "/stats"}
+	usersWithTimeout := config.Backend{Host: "http://users.example.com", ConnectTimeout: time.Second}
+	other := config.Backend{Host: "http://other.example.com"}
+
+	pool := newTransportPool(cfg)
+	forBackend := func(backend config.Backend) *http.Transport {
+		transport, err := pool.forBackend(backend)
+		require.NoError(t, err)
+		return transport.(*http.Transport)
+	}
+
+	transport := forBackend(users)
+	assert.Equal(t, 64, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 10, transport.MaxConnsPerHost)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+
+	// Backends targeting the same host share a transport
+	assert.Same(t, transport, forBackend(usersStats))
+
+	// Backend connection timeouts override the host settings
+	assert.NotSame(t, transport, forBackend(usersWithTimeout))
+
+	// Other hosts use the defaults
+	otherTransport := forBackend(other)
+	assert.Equal(t, 0, otherTransport.MaxConnsPerHost)
+	assert.True(t, otherTransport.ForceAttemptHTTP2)
+}
+
+func TestTransportPool_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert := testcert.Write(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client", r.TLS.PeerCertificates[0].Subject.CommonName)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	backend.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	backend.StartTLS()
+	defer backend.Close()
+
+	// Trust the test server's certificate as a custom CA
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+	tests := []struct {
+		name      string
+		tls       *config.TLS
+		expectErr bool
+	}{
+		{
+			name: "client certificate and custom CA",
+			tls: &config.TLS{
+				CAFile:     caFile,
+				CertFile:   clientCert.CertFile,
+				KeyFile:    clientCert.KeyFile,
+				MinVersion: "1.2",
+			},
+		},
+		{
+			name:      "missing client certificate",
+			tls:       &config.TLS{CAFile: caFile},
+			expectErr: true,
+		},
+		{
+			name:      "unknown CA",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newTransportPool(&config.Config{})
+			transport, err := pool.forBackend(config.Backend{Host: backend.URL, TLS: tt.tls})
+			require.NoError(t, err)
+
+			resp, err := (&http.Client{Transport: transport}).Get(backend.URL)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		})
+	}
+}
+
+func TestTransportPool_InvalidTLS(t *testing.T) {
+	pool := newTransportPool(&config.Config{})
+	_, err := pool.forBackend(config.Backend{
+		Host: "https://users.example.com",
+		TLS:  &config.TLS{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	})
+	assert.Error(t, err)
+}
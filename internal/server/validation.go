@@ -0,0 +1,218 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+	"github.com/TrueTickets/api-aggregator/internal/schema"
+)
+
+// requestValidator checks incoming requests against an endpoint's request schema
+// and parameter constraints before any backend is called
+type requestValidator struct {
+	schema      *schema.Schema
+	pathParams  map[string]paramRule
+	queryParams map[string]paramRule
+}
+
+// paramRule is a parameter constraint with its pattern compiled
+type paramRule struct {
+	config.ParamConstraint
+	pattern *regexp.Regexp
+}
+
+// validationError is a single failure reported in a 400 response
+type validationError struct {
+	// Where the failure is: path, query or body
+	Location string `json:"location"`
+
+	// Parameter name, or JSON Pointer into the body; omitted for the body as a whole
+	Field string `json:"field,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// newRequestValidator creates the validator for an endpoint, nil if it validates nothing.
+// The configuration is checked when it is loaded, so errors here mean a schema file changed since.
+func newRequestValidator(endpoint config.Endpoint) (*requestValidator, error) {
+	if endpoint.RequestSchema == nil && endpoint.RequestSchemaFile == "" &&
+		len(endpoint.PathParams) == 0 && len(endpoint.QueryParams) == 0 {
+		return nil, nil
+	}
+
+	v := &requestValidator{}
+
+	document, err := endpoint.LoadRequestSchema()
+	if err != nil {
+		return nil, err
+	}
+	if document != nil {
+		if v.schema, err = schema.Compile(document); err != nil {
+			return nil, err
+		}
+	}
+
+	if v.pathParams, err = compileParamRules(endpoint.PathParams); err != nil {
+		return nil, err
+	}
+	if v.queryParams, err = compileParamRules(endpoint.QueryParams); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compileParamRules compiles the patterns of parameter constraints
+func compileParamRules(constraints map[string]config.ParamConstraint) (map[string]paramRule, error) {
+	rules := make(map[string]paramRule, len(constraints))
+	for name, constraint := range constraints {
+		pattern, err := regexp.Compile(constraint.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: invalid pattern: %w", name, err)
+		}
+		rules[name] = paramRule{ParamConstraint: constraint, pattern: pattern}
+	}
+	return rules, nil
+}
+
+// validate returns the failures of a request, path and query parameters first
+func (v *requestValidator) validate(pathParams map[string]string, query url.Values, body []byte) []validationError {
+	var errs []validationError
+
+	for _, name := range sortedRuleNames(v.pathParams) {
+		if message := v.pathParams[name].check(pathParams[name]); message != "" {
+			errs = append(errs, validationError{Location: "path", Field: name, Message: message})
+		}
+	}
+
+	for _, name := range sortedRuleNames(v.queryParams) {
+		rule := v.queryParams[name]
+		values, present := query[name]
+		if !present {
+			if rule.Required {
+				errs = append(errs, validationError{Location: "query", Field: name, Message: "is required"})
+			}
+			continue
+		}
+		for _, value := range values {
+			if message := rule.check(value); message != "" {
+				errs = append(errs, validationError{Location: "query", Field: name, Message: message})
+				break
+			}
+		}
+	}
+
+	if v.schema != nil {
+		errs = append(errs, v.validateBody(body)...)
+	}
+	return errs
+}
+
+// validateBody validates a JSON request body against the request schema
+func (v *requestValidator) validateBody(body []byte) []validationError {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return []validationError{{Location: "body", Message: "request body is required"}}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []validationError{{Location: "body", Message: "invalid JSON: " + err.Error()}}
+	}
+
+	var errs []validationError
+	for _, err := range v.schema.Validate(value) {
+		errs = append(errs, validationError{Location: "body", Field: err.Path, Message: err.Message})
+	}
+	return errs
+}
+
+// check returns why a parameter value does not satisfy the rule, or an empty string if it does
+func (r paramRule) check(value string) string {
+	switch r.Type {
+	case "int":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "must be an integer"
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "must be a number"
+		}
+	case "boolean":
+		if value != "true" && value != "false" {
+			return "must be true or false"
+		}
+	case "uuid":
+		if !schema.ValidFormat("uuid", value) {
+			return "must be a valid uuid"
+		}
+	}
+
+	if r.Pattern != "" && !r.pattern.MatchString(value) {
+		return "must match pattern " + r.Pattern
+	}
+	if len(r.Enum) > 0 && !slices.Contains(r.Enum, value) {
+		return "must be one of " + strings.Join(r.Enum, ", ")
+	}
+	return ""
+}
+
+// validateRequest checks a request against the endpoint's validator, writing a 400 response
+// and returning false if it is invalid. A body read for validation is restored for the backends.
+func (s *Server) validateRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	v *requestValidator,
+	pathParams map[string]string,
+) bool {
+	var body []byte
+	if v.schema != nil && r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to read request body for validation")
+		}
+		if err := r.Body.Close(); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to close request body")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	errs := v.validate(pathParams, r.URL.Query(), body)
+	if len(errs) == 0 {
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "request validation failed",
+		"errors": errs,
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to encode validation error response")
+	}
+	return false
+}
+
+// sortedRuleNames returns the parameter names of rules in sorted order
+func sortedRuleNames(rules map[string]paramRule) []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestServer_RequestValidation(t *testing.T) {
+	var calls atomic.Int32
+	var receivedBody atomic.Value
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		receivedBody.Store(string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"ok": true}`))
+		assert.NoError(t, err)
+	}))
+	defer backend.Close()
+
+	cfg := createTestConfig(http.MethodPost, backend.URL)
+	cfg.Endpoints[0].Endpoint = "/orders/{id}"
+	cfg.Endpoints[0].RequestSchema = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"sku"},
+		"properties": map[string]interface{}{
+			"sku":      map[string]interface{}{"type": "string"},
+			"quantity": map[string]interface{}{"type": "integer", "minimum": 1},
+		},
+	}
+	cfg.Endpoints[0].PathParams = map[string]config.ParamConstraint{"id": {Type: "int"}}
+	cfg.Endpoints[0].QueryParams = map[string]config.ParamConstraint{
+		"channel": {Type: "string", Enum: []string{"web", "mobile"}, Required: true},
+		"dry_run": {Type: "boolean"},
+	}
+
+	server := createTestServer(cfg)
+
+	tests := []struct {
+		name           string
+		target         string
+		body           string
+		expectedStatus int
+		expectedErrors []validationError
+	}{
+		{
+			name:           "valid request",
+			target:         "/orders/42?channel=web&dry_run=true",
+			body:           `{"sku": "ABC-1", "quantity": 2}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid parameters and body",
+			target:         "/orders/abc?dry_run=maybe",
+			body:           `{"quantity": 0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []validationError{
+				{Location: "path", Field: "id", Message: "must be an integer"},
+				{Location: "query", Field: "channel", Message: "is required"},
+				{Location: "query", Field: "dry_run", Message: "must be true or false"},
+				{Location: "body", Message: `missing required property "sku"`},
+				{Location: "body", Field: "/quantity", Message: "must be greater than or equal to 1"},
+			},
+		},
+		{
+			name:           "enum and malformed body",
+			target:         "/orders/42?channel=fax",
+			body:           `{"sku": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []validationError{
+				{Location: "query", Field: "channel", Message: "must be one of web, mobile"},
+				{Location: "body", Message: "invalid JSON: unexpected end of JSON input"},
+			},
+		},
+		{
+			name:           "missing body",
+			target:         "/orders/42?channel=web",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []validationError{{Location: "body", Message: "request body is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// The body read for validation is still forwarded
+				assert.Equal(t, int32(1), calls.Load())
+				assert.Equal(t, tt.body, receivedBody.Load())
+				return
+			}
+
+			assert.Equal(t, int32(0), calls.Load())
+			var response struct {
+				Error  string            `json:"error"`
+				Errors []validationError `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "request validation failed", response.Error)
+			assert.Equal(t, tt.expectedErrors, response.Errors)
+		})
+	}
+}
+
+func TestServer_RequestValidationUnavailable(t *testing.T) {
+	cfg := createTestConfig(http.MethodPost, "http://backend")
+	cfg.Endpoints[0].RequestSchemaFile = "testdata/missing-schema.json"
+
+	server := createTestServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Request validation unavailable")
+}
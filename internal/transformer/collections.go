@@ -0,0 +1,383 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+// ApplyCollections applies collection operations to arrays in the data, in declared order
+func (t *Transformer) ApplyCollections(
+	ctx context.Context,
+	data interface{},
+	operations []config.CollectionOperation,
+) interface{} {
+	_, span := t.tracer.Start(ctx, "apply_collections")
+	defer span.End()
+
+	for _, operation := range operations {
+		data = t.applyCollection(data, operation)
+	}
+
+	return data
+}
+
+// applyCollection applies a single collection operation to the array at the operation path
+func (t *Transformer) applyCollection(data interface{}, operation config.CollectionOperation) interface{} {
+	// Top-level array response
+	if operation.Path == "" {
+		items, ok := data.([]interface{})
+		if !ok {
+			return data
+		}
+		return t.ApplyCollectionOperation(items, operation)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	items, ok := t.GetNestedField(dataMap, operation.Path).([]interface{})
+	if !ok {
+		return data
+	}
+
+	t.SetNestedField(dataMap, operation.Path, t.ApplyCollectionOperation(items, operation))
+	return dataMap
+}
+
+// ApplyCollectionOperation applies a single operation to an array and returns the new array
+func (t *Transformer) ApplyCollectionOperation(items []interface{}, operation config.CollectionOperation) []interface{} {
+	switch {
+	case len(operation.Sort) > 0:
+		return t.SortItems(items, operation.Sort)
+	case operation.Dedupe != "":
+		return t.DedupeItems(items, operation.Dedupe)
+	case operation.Filter != nil:
+		return t.FilterItems(items, *operation.Filter)
+	default:
+		return t.PaginateItems(items, operation.Offset, operation.Limit)
+	}
+}
+
+// SortItems sorts items by the given fields using a stable, type-aware comparison.
+// Items missing a sort field are placed last regardless of order.
+func (t *Transformer) SortItems(items []interface{}, fields []config.SortField) []interface{} {
+	result := make([]interface{}, len(items))
+	copy(result, items)
+
+	// Strings are compared as timestamps only if every string value of the field is one,
+	// so the ordering stays consistent whatever the order of the input
+	timestamps := make([]bool, len(fields))
+	for k, field := range fields {
+		timestamps[k] = t.allTimestamps(result, field.Field)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		for k, field := range fields {
+			a := t.getItemField(result[i], field.Field)
+			b := t.getItemField(result[j], field.Field)
+
+			// Missing values always sort last
+			if a == nil || b == nil {
+				if a == nil && b == nil {
+					continue
+				}
+				return b == nil
+			}
+
+			cmp := compareValuesAs(a, b, timestamps[k])
+			if cmp == 0 {
+				continue
+			}
+			if field.Order == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	return result
+}
+
+// allTimestamps reports whether every string value of a field is an RFC 3339 timestamp
+func (t *Transformer) allTimestamps(items []interface{}, field string) bool {
+	for _, item := range items {
+		value, ok := t.getItemField(item, field).(string)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// DedupeItems removes items with a duplicate value for the key field, keeping the first occurrence.
+// Items without the key field are always kept.
+func (t *Transformer) DedupeItems(items []interface{}, key string) []interface{} {
+	result := make([]interface{}, 0, len(items))
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		value := t.getItemField(item, key)
+		if value == nil {
+			result = append(result, item)
+			continue
+		}
+
+		identity := ValueKey(value)
+		if seen[identity] {
+			continue
+		}
+		seen[identity] = true
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// FilterItems keeps only the items matching the predicate
+func (t *Transformer) FilterItems(items []interface{}, predicate config.FilterPredicate) []interface{} {
+	result := make([]interface{}, 0, len(items))
+
+	for _, item := range items {
+		if matchesPredicate(t.getItemField(item, predicate.Field), predicate) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// PaginateItems skips offset items and returns at most limit items (0 means no limit)
+func (t *Transformer) PaginateItems(items []interface{}, offset, limit int) []interface{} {
+	if offset >= len(items) {
+		return []interface{}{}
+	}
+
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	result := make([]interface{}, end-offset)
+	copy(result, items[offset:end])
+	return result
+}
+
+// ItemKey returns the comparable identity of an item's key field, or false if the field is missing
+func (t *Transformer) ItemKey(item interface{}, field string) (string, bool) {
+	value := t.getItemField(item, field)
+	if value == nil {
+		return "", false
+	}
+	return ValueKey(value), true
+}
+
+// getItemField gets a field from an array item, returning nil for non-object items
+func (t *Transformer) getItemField(item interface{}, field string) interface{} {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return t.GetNestedField(itemMap, field)
+}
+
+// matchesPredicate evaluates a filter predicate against a field value
+func matchesPredicate(value interface{}, predicate config.FilterPredicate) bool {
+	switch predicate.Operator {
+	case "exists":
+		want, ok := predicate.Value.(bool)
+		if !ok {
+			want = true
+		}
+		return (value != nil) == want
+	case "eq":
+		return value != nil && valuesEqual(value, predicate.Value)
+	case "ne":
+		return value == nil || !valuesEqual(value, predicate.Value)
+	case "gt", "gte", "lt", "lte":
+		if value == nil || predicate.Value == nil || typeRank(value) != typeRank(predicate.Value) {
+			return false
+		}
+		cmp := compareValues(value, predicate.Value)
+		switch predicate.Operator {
+		case "gt":
+			return cmp > 0
+		case "gte":
+			return cmp >= 0
+		case "lt":
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case "in", "nin":
+		found := false
+		if candidates, ok := predicate.Value.([]interface{}); ok && value != nil {
+			for _, candidate := range candidates {
+				if valuesEqual(value, candidate) {
+					found = true
+					break
+				}
+			}
+		}
+		return found == (predicate.Operator == "in")
+	case "contains":
+		return containsValue(value, predicate.Value)
+	default:
+		return false
+	}
+}
+
+// containsValue reports whether a string contains a substring or an array contains an element
+func containsValue(value, needle interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		s, ok := needle.(string)
+		return ok && strings.Contains(v, s)
+	case []interface{}:
+		for _, element := range v {
+			if valuesEqual(element, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// valuesEqual compares two values, treating numbers of different types as equal when numerically equal
+func valuesEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if typeRank(a) != typeRank(b) {
+		return false
+	}
+	return compareValues(a, b) == 0
+}
+
+// Type ranks used to order values of different types
+const (
+	rankNumber = iota
+	rankString
+	rankBool
+	rankOther
+)
+
+// typeRank returns the ordering rank of a value's type
+func typeRank(value interface{}) int {
+	if _, ok := toFloat(value); ok {
+		return rankNumber
+	}
+	switch value.(type) {
+	case string:
+		return rankString
+	case bool:
+		return rankBool
+	default:
+		return rankOther
+	}
+}
+
+// compareValues compares two values: numbers numerically, RFC 3339 timestamps chronologically,
+// strings lexically and booleans with false first. Values of different types are ordered by type.
+func compareValues(a, b interface{}) int {
+	return compareValuesAs(a, b, true)
+}
+
+// compareValuesAs compares two values, treating strings as timestamps only when both parse
+// and timestamps is set
+func compareValuesAs(a, b interface{}, timestamps bool) int {
+	rankA, rankB := typeRank(a), typeRank(b)
+	if rankA != rankB {
+		if rankA < rankB {
+			return -1
+		}
+		return 1
+	}
+
+	switch rankA {
+	case rankNumber:
+		x, _ := toFloat(a)
+		y, _ := toFloat(b)
+		return compareOrdered(x, y)
+	case rankString:
+		x, y := a.(string), b.(string)
+		if !timestamps {
+			return strings.Compare(x, y)
+		}
+		if tx, err := time.Parse(time.RFC3339, x); err == nil {
+			if ty, err := time.Parse(time.RFC3339, y); err == nil {
+				return tx.Compare(ty)
+			}
+		}
+		return strings.Compare(x, y)
+	case rankBool:
+		x, y := a.(bool), b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+// compareOrdered compares two floats
+func compareOrdered(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toFloat converts numeric values decoded from JSON, XML or YAML to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// ValueKey builds a comparable identity for a value so that 1 and 1.0 are treated as the same key
+func ValueKey(value interface{}) string {
+	if f, ok := toFloat(value); ok {
+		return fmt.Sprintf("n:%v", f)
+	}
+	return fmt.Sprintf("%T:%v", value, value)
+}
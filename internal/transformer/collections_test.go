@@ -0,0 +1,227 @@
+// Copyright (c) 2025 True Tickets, Inc.
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
+
+func TestTransformer_SortItems(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	transformer := New(Config{Tracer: tracer})
+
+	tests := []struct {
+		name     string
+		items    []interface{}
+		fields   []config.SortField
+		expected []interface{}
+	}{
+		{
+			name: "numeric ascending",
+			items: []interface{}{
+				map[string]interface{}{"id": float64(3)},
+				map[string]interface{}{"id": float64(1)},
+				map[string]interface{}{"id": float64(2)},
+			},
+			fields: []config.SortField{{Field: "id", Order: "asc"}},
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				map[string]interface{}{"id": float64(2)},
+				map[string]interface{}{"id": float64(3)},
+			},
+		},
+		{
+			name: "numeric descending is not lexical",
+			items: []interface{}{
+				map[string]interface{}{"id": float64(9)},
+				map[string]interface{}{"id": float64(10)},
+			},
+			fields: []config.SortField{{Field: "id", Order: "desc"}},
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(10)},
+				map[string]interface{}{"id": float64(9)},
+			},
+		},
+		{
+			name: "multiple fields",
+			items: []interface{}{
+				map[string]interface{}{"type": "post", "id": float64(2)},
+				map[string]interface{}{"type": "cart", "id": float64(1)},
+				map[string]interface{}{"type": "post", "id": float64(1)},
+			},
+			fields: []config.SortField{{Field: "type", Order: "asc"}, {Field: "id", Order: "desc"}},
+			expected: []interface{}{
+				map[string]interface{}{"type": "cart", "id": float64(1)},
+				map[string]interface{}{"type": "post", "id": float64(2)},
+				map[string]interface{}{"type": "post", "id": float64(1)},
+			},
+		},
+		{
+			name: "timestamps and missing values",
+			items: []interface{}{
+				map[string]interface{}{"at": "2024-01-02T00:00:00+02:00"},
+				map[string]interface{}{"name": "no timestamp"},
+				map[string]interface{}{"at": "2024-01-01T23:00:00Z"},
+			},
+			fields: []config.SortField{{Field: "at", Order: "desc"}},
+			expected: []interface{}{
+				map[string]interface{}{"at": "2024-01-01T23:00:00Z"},
+				map[string]interface{}{"at": "2024-01-02T00:00:00+02:00"},
+				map[string]interface{}{"name": "no timestamp"},
+			},
+		},
+		{
+			name: "timestamps mixed with other strings sort lexically",
+			items: []interface{}{
+				map[string]interface{}{"at": "2024-01-02T00:00:00+02:00"},
+				map[string]interface{}{"at": "2024-01-01T23:00:00Z"},
+				map[string]interface{}{"at": "2024-01-01T23:30:00"},
+			},
+			fields: []config.SortField{{Field: "at", Order: "asc"}},
+			expected: []interface{}{
+				map[string]interface{}{"at": "2024-01-01T23:00:00Z"},
+				map[string]interface{}{"at": "2024-01-01T23:30:00"},
+				map[string]interface{}{"at": "2024-01-02T00:00:00+02:00"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := transformer.SortItems(tt.items, tt.fields)
+			assert.Equal(t, tt.expected, result)
+
+			// The order does not depend on the input order
+			reversed := make([]interface{}, len(tt.items))
+			for i, item := range tt.items {
+				reversed[len(tt.items)-1-i] = item
+			}
+			assert.Equal(t, tt.expected, transformer.SortItems(reversed, tt.fields))
+		})
+	}
+}
+
+func TestTransformer_DedupeItems(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	transformer := New(Config{Tracer: tracer})
+
+	items := []interface{}{
+		map[string]interface{}{"id": float64(1), "source": "a"},
+		map[string]interface{}{"id": 1, "source": "b"},
+		map[string]interface{}{"id": "1", "source": "c"},
+		map[string]interface{}{"source": "d"},
+		map[string]interface{}{"source": "e"},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"id": float64(1), "source": "a"},
+		map[string]interface{}{"id": "1", "source": "c"},
+		map[string]interface{}{"source": "d"},
+		map[string]interface{}{"source": "e"},
+	}
+
+	assert.Equal(t, expected, transformer.DedupeItems(items, "id"))
+}
+
+func TestTransformer_FilterItems(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	transformer := New(Config{Tracer: tracer})
+
+	items := []interface{}{
+		map[string]interface{}{"id": float64(1), "completed": true, "tags": []interface{}{"a"}},
+		map[string]interface{}{"id": float64(2), "completed": false, "tags": []interface{}{"b"}},
+		map[string]interface{}{"id": float64(3), "title": "hello world"},
+	}
+
+	tests := []struct {
+		name      string
+		predicate config.FilterPredicate
+		expected  []float64
+	}{
+		{"eq bool", config.FilterPredicate{Field: "completed", Operator: "eq", Value: false}, []float64{2}},
+		{"ne bool", config.FilterPredicate{Field: "completed", Operator: "ne", Value: true}, []float64{2, 3}},
+		{"gt number with int value", config.FilterPredicate{Field: "id", Operator: "gt", Value: 1}, []float64{2, 3}},
+		{"lte number", config.FilterPredicate{Field: "id", Operator: "lte", Value: 2}, []float64{1, 2}},
+		{"in list", config.FilterPredicate{Field: "id", Operator: "in", Value: []interface{}{1, 3}}, []float64{1, 3}},
+		{"nin list", config.FilterPredicate{Field: "id", Operator: "nin", Value: []interface{}{1, 3}}, []float64{2}},
+		{"contains string", config.FilterPredicate{Field: "title", Operator: "contains", Value: "world"}, []float64{3}},
+		{"contains element", config.FilterPredicate{Field: "tags", Operator: "contains", Value: "b"}, []float64{2}},
+		{"exists", config.FilterPredicate{Field: "completed", Operator: "exists"}, []float64{1, 2}},
+		{"not exists", config.FilterPredicate{Field: "completed", Operator: "exists", Value: false}, []float64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := transformer.FilterItems(items, tt.predicate)
+			ids := make([]float64, 0, len(result))
+			for _, item := range result {
+				ids = append(ids, item.(map[string]interface{})["id"].(float64))
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestTransformer_PaginateItems(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	transformer := New(Config{Tracer: tracer})
+
+	items := []interface{}{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []interface{}{1, 2}, transformer.PaginateItems(items, 0, 2))
+	assert.Equal(t, []interface{}{3, 4}, transformer.PaginateItems(items, 2, 2))
+	assert.Equal(t, []interface{}{4, 5}, transformer.PaginateItems(items, 3, 0))
+	assert.Equal(t, []interface{}{}, transformer.PaginateItems(items, 10, 2))
+}
+
+func TestTransformer_ApplyCollections(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	transformer := New(Config{Tracer: tracer})
+
+	data := map[string]interface{}{
+		"feed": map[string]interface{}{
+			"activities": []interface{}{
+				map[string]interface{}{"id": float64(2), "completed": false},
+				map[string]interface{}{"id": float64(1), "completed": false},
+				map[string]interface{}{"id": float64(2), "completed": false},
+				map[string]interface{}{"id": float64(3), "completed": true},
+				map[string]interface{}{"id": float64(4), "completed": false},
+			},
+		},
+	}
+
+	operations := []config.CollectionOperation{
+		{Path: "feed.activities", Dedupe: "id"},
+		{Path: "feed.activities", Filter: &config.FilterPredicate{Field: "completed", Operator: "eq", Value: false}},
+		{Path: "feed.activities", Sort: []config.SortField{{Field: "id", Order: "desc"}}},
+		{Path: "feed.activities", Limit: 2},
+		{Path: "missing.path", Limit: 1},
+	}
+
+	expected := map[string]interface{}{
+		"feed": map[string]interface{}{
+			"activities": []interface{}{
+				map[string]interface{}{"id": float64(4), "completed": false},
+				map[string]interface{}{"id": float64(2), "completed": false},
+			},
+		},
+	}
+
+	result := transformer.ApplyCollections(context.Background(), data, operations)
+	assert.Equal(t, expected, result)
+
+	// Top-level arrays are addressed with an empty path
+	array := []interface{}{float64(2), float64(1)}
+	result = transformer.ApplyCollections(context.Background(), array, []config.CollectionOperation{
+		{Sort: []config.SortField{{Field: "id", Order: "asc"}}},
+		{Limit: 1},
+	})
+	assert.Equal(t, []interface{}{float64(2)}, result)
+}
@@ -3,11 +3,21 @@
 
 package types
 
-import "github.com/TrueTickets/api-aggregator/internal/config"
+import (
+	"net/http"
+
+	"github.com/TrueTickets/api-aggregator/internal/config"
+)
 
 // BackendResponse represents a response from a backend service
 type BackendResponse struct {
 	Backend config.Backend
 	Data    interface{}
 	Error   error
+
+	// Response headers from the backend
+	Header http.Header
+
+	// Fallback that served the response, nil if the primary backend responded
+	Fallback *config.Fallback
 }